@@ -16,12 +16,17 @@ func main() {
 
 	waLogger := walog.Stdout("WA", "ERROR", true)
 
+	if err := usecase.ConfigureTyping(cfg.TypingMinDelay, cfg.TypingMaxDelay); err != nil {
+		log.Fatal(err)
+	}
+
 	waManager := wa.NewManager(cfg.SQLitePath, waLogger)
 	go func() {
 		if err := waManager.AutoConnectExisting(context.Background()); err != nil {
 			log.Printf("auto connect existing sessions: %v", err)
 		}
 	}()
+	go waManager.RunWebhooks(context.Background())
 
 	pairUC := usecase.NewPairCodeUsecase(waManager)
 	listUC := usecase.NewListClientsUsecase(waManager)
@@ -32,8 +37,28 @@ func main() {
 	stopUC := usecase.NewStopSessionUsecase(waManager)
 	delFUC := usecase.NewDeleteSessionForceUsecase(waManager)
 	sendUC := usecase.NewSendTextUsecase(waManager)
+	sendImgUC := usecase.NewSendImageUsecase(waManager)
+	sendDocUC := usecase.NewSendDocumentUsecase(waManager)
+	sendAudUC := usecase.NewSendAudioUsecase(waManager)
+	sendVidUC := usecase.NewSendVideoUsecase(waManager)
+	sendStkUC := usecase.NewSendStickerUsecase(waManager)
+	locUC := usecase.NewSendLocationUsecase(waManager)
+	liveUC := usecase.NewLiveLocationUsecase(waManager)
+	contactUC := usecase.NewSendContactUsecase(waManager)
+	reactUC := usecase.NewReactMessageUsecase(waManager)
+	editUC := usecase.NewEditMessageUsecase(waManager)
+	revokeUC := usecase.NewRevokeMessageUsecase(waManager)
+	pollUC := usecase.NewSendPollUsecase(waManager)
+	disappearUC := usecase.NewDisappearingUsecase(waManager)
+	statusUC := usecase.NewStatusUsecase(waManager)
+	newsletterUC := usecase.NewNewsletterUsecase(waManager)
+	markReadUC := usecase.NewMarkReadUsecase(waManager)
+	settingsUC := usecase.NewSessionSettingsUsecase(waManager)
+	deadLetterUC := usecase.NewWebhookDeadLettersUsecase(waManager)
+	eventsUC := usecase.NewEventsUsecase(waManager)
 
-	handler := http.NewHandler(pairUC, listUC, meUC, pairSU, sessUC, delUC, stopUC, delFUC, sendUC)
+	handler := http.NewHandler(pairUC, listUC, meUC, pairSU, sessUC, delUC, stopUC, delFUC, sendUC, sendImgUC, sendDocUC, sendAudUC, sendVidUC, sendStkUC, locUC, liveUC, contactUC, reactUC, editUC, revokeUC, pollUC, disappearUC, statusUC, newsletterUC, markReadUC, settingsUC, deadLetterUC, eventsUC)
+	http.ConfigureWebSocket(cfg.WSAllowedOrigins)
 	router := http.NewRouter(handler)
 
 	log.Printf("HTTP listening on :%s", cfg.Port)
@@ -1,5 +1,10 @@
 package http
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type PairCodeRequest struct {
 	Phone string `json:"phone"`
 }
@@ -10,9 +15,38 @@ type PairCodeResponse struct {
 	JID         string `json:"jid,omitempty"`
 }
 
+type ReplyToRequest struct {
+	MessageID   string `json:"message_id"`
+	Participant string `json:"participant"`
+	Text        string `json:"text"`
+}
+
+// SendOptionsRequest holds the options shared by every send endpoint. In
+// multipart forms, reply_to is sent as a JSON-encoded field.
+type SendOptionsRequest struct {
+	ReplyTo        *ReplyToRequest `json:"reply_to" form:"-"`
+	SimulateTyping bool            `json:"simulate_typing" form:"simulate_typing"`
+	Async          bool            `json:"async" form:"async"`
+}
+
 type SendTextRequest struct {
-	To      string `json:"to"`
-	Message string `json:"message"`
+	To          string              `json:"to"`
+	Message     string              `json:"message"`
+	Mentions    []string            `json:"mentions"`
+	MentionAll  bool                `json:"mention_all"`
+	LinkPreview bool                `json:"link_preview"`
+	Preview     *LinkPreviewRequest `json:"preview"`
+	SendOptionsRequest
+}
+
+// LinkPreviewRequest sets the preview of the message's link by hand.
+// Thumbnail is base64 or a data URI.
+type LinkPreviewRequest struct {
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Thumbnail    string `json:"thumbnail"`
+	ThumbnailURL string `json:"thumbnail_url"`
 }
 
 type SendTextResponse struct {
@@ -20,6 +54,257 @@ type SendTextResponse struct {
 	MessageID string `json:"message_id,omitempty"`
 }
 
+// SendMediaRequest holds the fields shared by every media endpoint. It binds
+// from JSON, where File is base64 or a data URI, and from multipart forms,
+// where the file is uploaded as the "file" part instead.
+type SendMediaRequest struct {
+	To       string `json:"to" form:"to"`
+	Caption  string `json:"caption" form:"caption"`
+	File     string `json:"file" form:"-"`
+	URL      string `json:"url" form:"url"`
+	Filename string `json:"filename" form:"filename"`
+	Mimetype string `json:"mimetype" form:"mimetype"`
+	SendOptionsRequest
+}
+
+type SendImageRequest struct {
+	SendMediaRequest
+	ViewOnce bool `json:"view_once" form:"view_once"`
+}
+
+type SendDocumentRequest struct {
+	SendMediaRequest
+}
+
+// SendAudioRequest sends a voice note when PTT is set, otherwise a regular
+// audio file.
+type SendAudioRequest struct {
+	SendMediaRequest
+	PTT      bool `json:"ptt" form:"ptt"`
+	ViewOnce bool `json:"view_once" form:"view_once"`
+}
+
+// SendVideoRequest optionally carries a preview image as a base64 "thumbnail"
+// field or a "thumbnail" multipart part.
+type SendVideoRequest struct {
+	SendMediaRequest
+	GifPlayback bool   `json:"gif_playback" form:"gif_playback"`
+	Thumbnail   string `json:"thumbnail" form:"-"`
+	ViewOnce    bool   `json:"view_once" form:"view_once"`
+}
+
+// SendStickerRequest accepts a 512x512 WebP, or a PNG/JPEG when Convert is
+// set.
+type SendStickerRequest struct {
+	SendMediaRequest
+	Convert bool `json:"convert" form:"convert"`
+}
+
+type SendMediaResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+// SendLocationRequest uses pointers for the coordinates so that 0 can be
+// told apart from a missing value.
+type SendLocationRequest struct {
+	To        string   `json:"to"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	URL       string   `json:"url"`
+	SendOptionsRequest
+}
+
+type SendLocationResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+type StartLiveLocationRequest struct {
+	To              string   `json:"to"`
+	Latitude        *float64 `json:"latitude"`
+	Longitude       *float64 `json:"longitude"`
+	AccuracyMeters  uint32   `json:"accuracy_meters"`
+	Caption         string   `json:"caption"`
+	DurationSeconds int      `json:"duration_seconds"`
+	IntervalSeconds int      `json:"interval_seconds"`
+}
+
+type UpdateLiveLocationRequest struct {
+	Latitude       *float64 `json:"latitude"`
+	Longitude      *float64 `json:"longitude"`
+	AccuracyMeters uint32   `json:"accuracy_meters"`
+}
+
+type LiveLocationResponse struct {
+	Status    string `json:"status"`
+	ID        string `json:"id"`
+	ExpiresIn int    `json:"expires_in,omitempty"`
+}
+
+type ContactPhoneRequest struct {
+	Number string `json:"number"`
+	Type   string `json:"type"`
+}
+
+type ContactRequest struct {
+	Name   string                `json:"name"`
+	Phones []ContactPhoneRequest `json:"phones"`
+	Email  string                `json:"email"`
+	Org    string                `json:"org"`
+}
+
+type SendContactRequest struct {
+	To       string           `json:"to"`
+	Contacts []ContactRequest `json:"contacts"`
+	SendOptionsRequest
+}
+
+type SendContactResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+// ReactMessageRequest sends Emoji as a reaction; an empty Emoji removes it.
+type ReactMessageRequest struct {
+	Chat        string `json:"chat"`
+	Participant string `json:"participant"`
+	Emoji       string `json:"emoji"`
+}
+
+type ReactMessageResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+type ReactionItemResponse struct {
+	Sender    string `json:"sender"`
+	Emoji     string `json:"emoji"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type MessageReactionsResponse struct {
+	MessageID string                 `json:"message_id"`
+	Reactions []ReactionItemResponse `json:"reactions"`
+}
+
+type EditMessageRequest struct {
+	Message string `json:"message"`
+}
+
+type EditMessageResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+type RevokeMessageResponse struct {
+	Status    string `json:"status"`
+	Accepted  bool   `json:"accepted"`
+	MessageID string `json:"message_id,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+type SendPollRequest struct {
+	To          string   `json:"to"`
+	Question    string   `json:"question"`
+	Options     []string `json:"options"`
+	MultiSelect bool     `json:"multi_select"`
+	SendOptionsRequest
+}
+
+type SendPollResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+type PollOptionResponse struct {
+	Name   string   `json:"name"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+type PollTallyResponse struct {
+	MessageID   string               `json:"message_id"`
+	Question    string               `json:"question"`
+	MultiSelect bool                 `json:"multi_select"`
+	Options     []PollOptionResponse `json:"options"`
+	UpdatedAt   int64                `json:"updated_at"`
+}
+
+// DisappearingRequest sets a disappearing-messages timer: off, 24h, 7d or 90d.
+type DisappearingRequest struct {
+	Timer string `json:"timer"`
+}
+
+type DisappearingResponse struct {
+	Chat      string `json:"chat,omitempty"`
+	Timer     string `json:"timer"`
+	Seconds   int64  `json:"seconds"`
+	Known     bool   `json:"known"`
+	SettingAt int64  `json:"setting_at,omitempty"`
+}
+
+// PostTextStatusRequest posts a text status. BackgroundColor is "#RRGGBB" or
+// "#AARRGGBB"; Font is a WhatsApp font name such as SYSTEM_BOLD. An empty
+// Recipients posts to the session's contacts.
+type PostTextStatusRequest struct {
+	Text            string   `json:"text"`
+	BackgroundColor string   `json:"background_color"`
+	Font            string   `json:"font"`
+	Recipients      []string `json:"recipients"`
+}
+
+// PostMediaStatusRequest carries its media like the media send endpoints.
+// Their to, reply_to, async and simulate_typing fields do not apply to a
+// status and are rejected.
+type PostMediaStatusRequest struct {
+	SendMediaRequest
+	Recipients []string `json:"recipients" form:"recipients"`
+}
+
+type StatusResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+type NewsletterResponse struct {
+	JID         string `json:"jid"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InviteLink  string `json:"invite_link,omitempty"`
+	Subscribers int    `json:"subscribers"`
+	Verified    bool   `json:"verified"`
+	State       string `json:"state,omitempty"`
+	Role        string `json:"role,omitempty"`
+	Muted       bool   `json:"muted"`
+}
+
+type NewsletterListResponse struct {
+	Newsletters []NewsletterResponse `json:"newsletters"`
+}
+
+type NewsletterMessageResponse struct {
+	ServerID  int            `json:"server_id"`
+	MessageID string         `json:"message_id"`
+	Type      string         `json:"type"`
+	Text      string         `json:"text,omitempty"`
+	Timestamp int64          `json:"timestamp"`
+	Views     int            `json:"views"`
+	Reactions map[string]int `json:"reactions"`
+}
+
+type NewsletterMessagesResponse struct {
+	JID      string                      `json:"jid"`
+	Messages []NewsletterMessageResponse `json:"messages"`
+}
+
+type NewsletterFollowResponse struct {
+	Status string `json:"status"`
+	JID    string `json:"jid"`
+}
+
 type PairStreamResponse struct {
 	Status      string `json:"status"`
 	PairingCode string `json:"pairing_code,omitempty"`
@@ -61,3 +346,96 @@ type ClientsResponse struct {
 	Count   int      `json:"count"`
 	Clients []string `json:"clients"`
 }
+
+// MarkReadRequest marks messages of a chat read. An empty MessageIDs marks
+// everything received since the chat was last marked read.
+type MarkReadRequest struct {
+	MessageIDs  []string `json:"message_ids"`
+	Participant string   `json:"participant"`
+}
+
+type MarkReadResponse struct {
+	Status     string   `json:"status"`
+	Chat       string   `json:"chat"`
+	MessageIDs []string `json:"message_ids"`
+}
+
+// SessionSettingsRequest updates the settings that are present.
+type SessionSettingsRequest struct {
+	AutoRead   *bool   `json:"auto_read"`
+	WebhookURL *string `json:"webhook_url"`
+	// WebhookEvents limits the webhook to these event types; [] sends all.
+	WebhookEvents []string `json:"webhook_events"`
+	// WebhookSecret rotates the signing secret; the old one keeps signing
+	// until retire_previous_secret. "" turns signing off.
+	WebhookSecret         *string `json:"webhook_secret"`
+	GenerateWebhookSecret bool    `json:"generate_webhook_secret"`
+	RetirePreviousSecret  bool    `json:"retire_previous_secret"`
+}
+
+type SessionSettingsResponse struct {
+	AutoRead        bool     `json:"auto_read"`
+	WebhookURL      string   `json:"webhook_url"`
+	WebhookEvents   []string `json:"webhook_events"`
+	WebhookSigned   bool     `json:"webhook_signed"`
+	WebhookRotating bool     `json:"webhook_secret_rotating"`
+	// WebhookSecret is only present in the response that set it.
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+}
+
+type DeadLetterResponse struct {
+	ID        int64           `json:"id"`
+	Session   string          `json:"session"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	CreatedAt int64           `json:"created_at"`
+	FailedAt  int64           `json:"failed_at"`
+}
+
+type DeadLettersResponse struct {
+	DeadLetters []DeadLetterResponse `json:"dead_letters"`
+}
+
+type DeadLettersChangeResponse struct {
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+}
+
+// EventResponse is a live gateway event, shaped like webhook deliveries.
+type EventResponse struct {
+	ID        string    `json:"id,omitempty"`
+	Session   string    `json:"session"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+}
+
+// SocketRequest is a control frame sent over /api/ws. Op is subscribe or
+// unsubscribe; "*" stands for every session or event type, and an empty
+// Events means every event type.
+type SocketRequest struct {
+	Op       string   `json:"op"`
+	Sessions []string `json:"sessions"`
+	Events   []string `json:"events"`
+}
+
+// SocketResponse is a frame sent by the gateway over /api/ws: an event, a gap
+// in a session's events, the subscriptions after a control frame, or an
+// error about a control frame.
+type SocketResponse struct {
+	Op            string              `json:"op"`
+	Event         *EventResponse      `json:"event,omitempty"`
+	Gap           *EventGapResponse   `json:"gap,omitempty"`
+	Subscriptions map[string][]string `json:"subscriptions,omitempty"`
+	Error         string              `json:"error,omitempty"`
+}
+
+// EventGapResponse is sent when some of a session's events after LastEventID
+// were missed and are no longer kept; the client should refetch current
+// state. LastEventID is empty when no event of the session was received.
+type EventGapResponse struct {
+	Session     string `json:"session"`
+	LastEventID string `json:"last_event_id"`
+}
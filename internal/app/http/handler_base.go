@@ -5,27 +5,65 @@ import (
 )
 
 type Handler struct {
-	pairUC *usecase.PairCodeUsecase
-	listUC *usecase.ListClientsUsecase
-	meUC   *usecase.MeUsecase
-	pairSU *usecase.PairStreamUsecase
-	sessUC *usecase.ListSessionsUsecase
-	delUC  *usecase.DeleteSessionUsecase
-	stopUC *usecase.StopSessionUsecase
-	delFUC *usecase.DeleteSessionForceUsecase
-	sendUC *usecase.SendTextUsecase
+	pairUC       *usecase.PairCodeUsecase
+	listUC       *usecase.ListClientsUsecase
+	meUC         *usecase.MeUsecase
+	pairSU       *usecase.PairStreamUsecase
+	sessUC       *usecase.ListSessionsUsecase
+	delUC        *usecase.DeleteSessionUsecase
+	stopUC       *usecase.StopSessionUsecase
+	delFUC       *usecase.DeleteSessionForceUsecase
+	sendUC       *usecase.SendTextUsecase
+	sendImgUC    *usecase.SendImageUsecase
+	sendDocUC    *usecase.SendDocumentUsecase
+	sendAudUC    *usecase.SendAudioUsecase
+	sendVidUC    *usecase.SendVideoUsecase
+	sendStkUC    *usecase.SendStickerUsecase
+	locUC        *usecase.SendLocationUsecase
+	liveUC       *usecase.LiveLocationUsecase
+	contactUC    *usecase.SendContactUsecase
+	reactUC      *usecase.ReactMessageUsecase
+	editUC       *usecase.EditMessageUsecase
+	revokeUC     *usecase.RevokeMessageUsecase
+	pollUC       *usecase.SendPollUsecase
+	disappearUC  *usecase.DisappearingUsecase
+	statusUC     *usecase.StatusUsecase
+	newsletterUC *usecase.NewsletterUsecase
+	markReadUC   *usecase.MarkReadUsecase
+	settingsUC   *usecase.SessionSettingsUsecase
+	deadLetterUC *usecase.WebhookDeadLettersUsecase
+	eventsUC     *usecase.EventsUsecase
 }
 
-func NewHandler(pairUC *usecase.PairCodeUsecase, listUC *usecase.ListClientsUsecase, meUC *usecase.MeUsecase, pairSU *usecase.PairStreamUsecase, sessUC *usecase.ListSessionsUsecase, delUC *usecase.DeleteSessionUsecase, stopUC *usecase.StopSessionUsecase, delFUC *usecase.DeleteSessionForceUsecase, sendUC *usecase.SendTextUsecase) *Handler {
+func NewHandler(pairUC *usecase.PairCodeUsecase, listUC *usecase.ListClientsUsecase, meUC *usecase.MeUsecase, pairSU *usecase.PairStreamUsecase, sessUC *usecase.ListSessionsUsecase, delUC *usecase.DeleteSessionUsecase, stopUC *usecase.StopSessionUsecase, delFUC *usecase.DeleteSessionForceUsecase, sendUC *usecase.SendTextUsecase, sendImgUC *usecase.SendImageUsecase, sendDocUC *usecase.SendDocumentUsecase, sendAudUC *usecase.SendAudioUsecase, sendVidUC *usecase.SendVideoUsecase, sendStkUC *usecase.SendStickerUsecase, locUC *usecase.SendLocationUsecase, liveUC *usecase.LiveLocationUsecase, contactUC *usecase.SendContactUsecase, reactUC *usecase.ReactMessageUsecase, editUC *usecase.EditMessageUsecase, revokeUC *usecase.RevokeMessageUsecase, pollUC *usecase.SendPollUsecase, disappearUC *usecase.DisappearingUsecase, statusUC *usecase.StatusUsecase, newsletterUC *usecase.NewsletterUsecase, markReadUC *usecase.MarkReadUsecase, settingsUC *usecase.SessionSettingsUsecase, deadLetterUC *usecase.WebhookDeadLettersUsecase, eventsUC *usecase.EventsUsecase) *Handler {
 	return &Handler{
-		pairUC: pairUC,
-		listUC: listUC,
-		meUC:   meUC,
-		pairSU: pairSU,
-		sessUC: sessUC,
-		delUC:  delUC,
-		stopUC: stopUC,
-		delFUC: delFUC,
-		sendUC: sendUC,
+		pairUC:       pairUC,
+		listUC:       listUC,
+		meUC:         meUC,
+		pairSU:       pairSU,
+		sessUC:       sessUC,
+		delUC:        delUC,
+		stopUC:       stopUC,
+		delFUC:       delFUC,
+		sendUC:       sendUC,
+		sendImgUC:    sendImgUC,
+		sendDocUC:    sendDocUC,
+		sendAudUC:    sendAudUC,
+		sendVidUC:    sendVidUC,
+		sendStkUC:    sendStkUC,
+		locUC:        locUC,
+		liveUC:       liveUC,
+		contactUC:    contactUC,
+		reactUC:      reactUC,
+		editUC:       editUC,
+		revokeUC:     revokeUC,
+		pollUC:       pollUC,
+		disappearUC:  disappearUC,
+		statusUC:     statusUC,
+		newsletterUC: newsletterUC,
+		markReadUC:   markReadUC,
+		settingsUC:   settingsUC,
+		deadLetterUC: deadLetterUC,
+		eventsUC:     eventsUC,
 	}
 }
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) ChatDisappearing(c *gin.Context) {
+	session := c.Param("session")
+	chat := c.Param("chat")
+	if session == "" || chat == "" {
+		c.JSON(400, gin.H{
+			"error": "session and chat params are required",
+		})
+		return
+	}
+
+	out, err := h.disappearUC.Get(c.Request.Context(), session, chat)
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "get disappearing timer failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, disappearingResponse(out))
+}
+
+func (h *Handler) SetChatDisappearing(c *gin.Context) {
+	session := c.Param("session")
+	chat := c.Param("chat")
+	if session == "" || chat == "" {
+		c.JSON(400, gin.H{
+			"error": "session and chat params are required",
+		})
+		return
+	}
+
+	h.setDisappearing(c, session, chat)
+}
+
+// DefaultDisappearing returns the timer the session's new chats start with.
+func (h *Handler) DefaultDisappearing(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	out, err := h.disappearUC.Get(c.Request.Context(), session, "")
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "get disappearing timer failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, disappearingResponse(out))
+}
+
+// SetDefaultDisappearing sets the timer the session's new chats start with.
+func (h *Handler) SetDefaultDisappearing(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	h.setDisappearing(c, session, "")
+}
+
+func (h *Handler) setDisappearing(c *gin.Context, session, chat string) {
+	var req DisappearingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+	if req.Timer == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "timer is required"})
+		return
+	}
+
+	out, err := h.disappearUC.Set(c.Request.Context(), usecase.SetDisappearingInput{
+		Session: session,
+		Chat:    chat,
+		Timer:   req.Timer,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "set disappearing timer failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, disappearingResponse(out))
+}
+
+func disappearingResponse(out *usecase.DisappearingOutput) DisappearingResponse {
+	resp := DisappearingResponse{
+		Chat:    out.Chat,
+		Timer:   out.Timer,
+		Seconds: out.Seconds,
+		Known:   out.Known,
+	}
+	if !out.SettingAt.IsZero() {
+		resp.SettingAt = out.SettingAt.Unix()
+	}
+	return resp
+}
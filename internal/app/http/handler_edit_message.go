@@ -0,0 +1,57 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) EditMessage(c *gin.Context) {
+	session := c.Param("session")
+	chat := c.Param("chat")
+	id := c.Param("id")
+	if session == "" || chat == "" || id == "" {
+		c.JSON(400, gin.H{
+			"error": "session, chat and id params are required",
+		})
+		return
+	}
+
+	var req EditMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message is required"})
+		return
+	}
+
+	out, err := h.editUC.Execute(c.Request.Context(), usecase.EditMessageInput{
+		Session:   session,
+		Chat:      chat,
+		MessageID: id,
+		Message:   req.Message,
+	})
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, usecase.ErrMessageNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, usecase.ErrEditWindowExpired), errors.Is(err, usecase.ErrMessageNotEditable):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": "edit message failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, EditMessageResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	socketPingInterval = 30 * time.Second
+	socketWriteTimeout = 10 * time.Second
+)
+
+// socketOrigins are the host patterns of pages allowed to open /api/ws from
+// another origin. Same-origin and non-browser clients are always allowed.
+var socketOrigins []string
+
+// ConfigureWebSocket sets the cross-origin host patterns, such as
+// "app.example.com" or "*.example.com", allowed to open /api/ws.
+func ConfigureWebSocket(origins []string) {
+	socketOrigins = origins
+}
+
+// EventsSocket streams live gateway events over a WebSocket. Nothing is sent
+// until the client subscribes, either with ?sessions=&events= (comma
+// separated) or with control frames such as
+// {"op":"subscribe","sessions":["wa-1"],"events":["message"]}.
+func (h *Handler) EventsSocket(c *gin.Context) {
+	filter := usecase.NewEventFilter()
+	if sessions := splitQuery(c.Query("sessions")); len(sessions) > 0 {
+		if err := filter.Subscribe(sessions, splitQuery(c.Query("events"))); err != nil {
+			c.JSON(400, gin.H{"error": "invalid subscription", "detail": err.Error()})
+			return
+		}
+	}
+
+	conn, err := websocket.Accept(socketWriter{c.Writer}, c.Request, &websocket.AcceptOptions{OriginPatterns: socketOrigins})
+	if err != nil {
+		// Accept has already answered the request.
+		return
+	}
+	defer conn.CloseNow()
+
+	// The request context ends once the connection is hijacked, so the
+	// socket gets its own.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := h.eventsUC.Listen(ctx, filter)
+
+	go func() {
+		defer cancel()
+		for {
+			var req SocketRequest
+			if err := wsjson.Read(ctx, conn, &req); err != nil {
+				var closeErr websocket.CloseError
+				if !errors.As(err, &closeErr) && ctx.Err() == nil {
+					_ = writeSocket(ctx, conn, SocketResponse{Op: "error", Error: "invalid control frame: " + err.Error()})
+					conn.Close(websocket.StatusUnsupportedData, "invalid control frame")
+				}
+				return
+			}
+			if err := writeSocket(ctx, conn, applySocketRequest(filter, req)); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(socketPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			pingCtx, cancelPing := context.WithTimeout(ctx, socketWriteTimeout)
+			err := conn.Ping(pingCtx)
+			cancelPing()
+			if err != nil {
+				return
+			}
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+			frame := SocketResponse{Op: "event", Event: eventResponse(evt)}
+			if evt.Gap {
+				frame = SocketResponse{Op: "gap", Gap: eventGapResponse(evt)}
+			}
+			if err := writeSocket(ctx, conn, frame); err != nil {
+				if ctx.Err() == nil {
+					log.Printf("write websocket event: %v", err)
+				}
+				return
+			}
+		}
+	}
+}
+
+// socketWriter lets websocket.Accept upgrade a gin response. gin refuses to
+// hijack a response it has written, so the 101 goes straight to the
+// underlying writer, which sends it when the connection is hijacked.
+type socketWriter struct {
+	w gin.ResponseWriter
+}
+
+func (s socketWriter) Header() http.Header {
+	return s.w.Header()
+}
+
+func (s socketWriter) Write(b []byte) (int, error) {
+	return s.w.Write(b)
+}
+
+func (s socketWriter) WriteHeader(code int) {
+	s.w.WriteHeader(code)
+	if code == http.StatusSwitchingProtocols {
+		if u, ok := s.w.(interface{ Unwrap() http.ResponseWriter }); ok {
+			u.Unwrap().WriteHeader(code)
+		}
+	}
+}
+
+func (s socketWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return s.w.Hijack()
+}
+
+func applySocketRequest(filter *usecase.EventFilter, req SocketRequest) SocketResponse {
+	switch req.Op {
+	case "subscribe":
+		if err := filter.Subscribe(req.Sessions, req.Events); err != nil {
+			return SocketResponse{Op: "error", Error: err.Error()}
+		}
+	case "unsubscribe":
+		filter.Unsubscribe(req.Sessions, req.Events)
+	default:
+		return SocketResponse{Op: "error", Error: "op must be subscribe or unsubscribe"}
+	}
+	return SocketResponse{Op: "subscriptions", Subscriptions: filter.Subscriptions()}
+}
+
+func writeSocket(ctx context.Context, conn *websocket.Conn, frame SocketResponse) error {
+	ctx, cancel := context.WithTimeout(ctx, socketWriteTimeout)
+	defer cancel()
+	return wsjson.Write(ctx, conn, frame)
+}
+
+func eventResponse(evt usecase.EventOutput) *EventResponse {
+	return &EventResponse{
+		ID:        evt.ID,
+		Session:   evt.Session,
+		Type:      evt.Type,
+		Timestamp: evt.Timestamp,
+		Data:      evt.Data,
+	}
+}
+
+func eventGapResponse(evt usecase.EventOutput) *EventGapResponse {
+	return &EventGapResponse{Session: evt.Session, LastEventID: evt.ID}
+}
+
+func splitQuery(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const eventStreamKeepAlive = 30 * time.Second
+
+// SessionEventsStream is an SSE feed of one session's events. Every event
+// carries its ID, so a client reconnecting with Last-Event-ID (or
+// ?last_event_id= where the header cannot be set) first gets what it missed.
+func (h *Handler) SessionEventsStream(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	lastID := lastEventID(c)
+	ctx := c.Request.Context()
+	stream, err := h.eventsUC.Stream(ctx, session, lastID)
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "open event stream failed", "detail": err.Error()})
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+	c.Writer.Flush()
+
+	if !stream.Complete {
+		writeStreamEvent(c, usecase.EventOutput{ID: lastID, Session: session, Gap: true})
+	}
+	for _, evt := range stream.Missed {
+		writeStreamEvent(c, evt)
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(eventStreamKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = c.Writer.WriteString(": keepalive\n\n")
+			c.Writer.Flush()
+		case evt, ok := <-stream.Live:
+			if !ok {
+				return
+			}
+			writeStreamEvent(c, evt)
+			c.Writer.Flush()
+		}
+	}
+}
+
+func writeStreamEvent(c *gin.Context, evt usecase.EventOutput) {
+	if evt.Gap {
+		c.Render(-1, sse.Event{Event: "gap", Data: eventGapResponse(evt)})
+		return
+	}
+	c.Render(-1, sse.Event{Id: evt.ID, Event: evt.Type, Data: eventResponse(evt)})
+}
+
+func lastEventID(c *gin.Context) string {
+	if id := c.GetHeader("Last-Event-ID"); id != "" {
+		return id
+	}
+	return c.Query("last_event_id")
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) MarkRead(c *gin.Context) {
+	session := c.Param("session")
+	chat := c.Param("chat")
+	if session == "" || chat == "" {
+		c.JSON(400, gin.H{
+			"error": "session and chat params are required",
+		})
+		return
+	}
+
+	// The body is optional: without one the whole chat is marked read.
+	var req MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+
+	out, err := h.markReadUC.Execute(c.Request.Context(), usecase.MarkReadInput{
+		Session:     session,
+		Chat:        chat,
+		MessageIDs:  req.MessageIDs,
+		Participant: req.Participant,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mark read failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MarkReadResponse{
+		Status:     out.Status,
+		Chat:       out.Chat,
+		MessageIDs: out.MessageIDs,
+	})
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) Newsletters(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	list, err := h.newsletterUC.List(c.Request.Context(), session)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "list channels failed", "detail": err.Error()})
+		return
+	}
+
+	out := make([]NewsletterResponse, 0, len(list))
+	for _, info := range list {
+		out = append(out, newsletterResponse(info))
+	}
+	c.JSON(http.StatusOK, NewsletterListResponse{Newsletters: out})
+}
+
+func (h *Handler) LookupNewsletter(c *gin.Context) {
+	session := c.Param("session")
+	invite := c.Query("invite")
+	if session == "" || invite == "" {
+		c.JSON(400, gin.H{
+			"error": "session param and invite query are required",
+		})
+		return
+	}
+
+	info, err := h.newsletterUC.Lookup(c.Request.Context(), session, invite)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lookup channel failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newsletterResponse(*info))
+}
+
+func (h *Handler) FollowNewsletter(c *gin.Context) {
+	session := c.Param("session")
+	jid := c.Param("jid")
+	if session == "" || jid == "" {
+		c.JSON(400, gin.H{
+			"error": "session and jid params are required",
+		})
+		return
+	}
+
+	if err := h.newsletterUC.Follow(c.Request.Context(), session, jid); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "follow channel failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewsletterFollowResponse{Status: "followed", JID: jid})
+}
+
+func (h *Handler) UnfollowNewsletter(c *gin.Context) {
+	session := c.Param("session")
+	jid := c.Param("jid")
+	if session == "" || jid == "" {
+		c.JSON(400, gin.H{
+			"error": "session and jid params are required",
+		})
+		return
+	}
+
+	if err := h.newsletterUC.Unfollow(c.Request.Context(), session, jid); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unfollow channel failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewsletterFollowResponse{Status: "unfollowed", JID: jid})
+}
+
+func (h *Handler) NewsletterMessages(c *gin.Context) {
+	session := c.Param("session")
+	jid := c.Param("jid")
+	if session == "" || jid == "" {
+		c.JSON(400, gin.H{
+			"error": "session and jid params are required",
+		})
+		return
+	}
+
+	count, err := queryInt(c, "count")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid count"})
+		return
+	}
+	before, err := queryInt(c, "before")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid before"})
+		return
+	}
+
+	in := usecase.NewsletterMessagesInput{Session: session, JID: jid, Count: count, Before: before}
+	items, err := h.newsletterUC.Messages(c.Request.Context(), in)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "channel messages failed", "detail": err.Error()})
+		return
+	}
+
+	messages := make([]NewsletterMessageResponse, 0, len(items))
+	for _, item := range items {
+		messages = append(messages, NewsletterMessageResponse{
+			ServerID:  item.ServerID,
+			MessageID: item.MessageID,
+			Type:      item.Type,
+			Text:      item.Text,
+			Timestamp: item.Timestamp.Unix(),
+			Views:     item.Views,
+			Reactions: item.Reactions,
+		})
+	}
+	c.JSON(http.StatusOK, NewsletterMessagesResponse{JID: jid, Messages: messages})
+}
+
+func newsletterResponse(info usecase.NewsletterInfo) NewsletterResponse {
+	return NewsletterResponse{
+		JID:         info.JID,
+		Name:        info.Name,
+		Description: info.Description,
+		InviteLink:  info.InviteLink,
+		Subscribers: info.Subscribers,
+		Verified:    info.Verified,
+		State:       info.State,
+		Role:        info.Role,
+		Muted:       info.Muted,
+	}
+}
+
+// queryInt reads an optional non-negative integer query parameter.
+func queryInt(c *gin.Context, name string) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return v, nil
+}
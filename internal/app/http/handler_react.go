@@ -0,0 +1,83 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) ReactMessage(c *gin.Context) {
+	session := c.Param("session")
+	id := c.Param("id")
+	if session == "" || id == "" {
+		c.JSON(400, gin.H{
+			"error": "session and id params are required",
+		})
+		return
+	}
+
+	var req ReactMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+	if req.Chat == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "chat is required"})
+		return
+	}
+
+	out, err := h.reactUC.Execute(c.Request.Context(), usecase.ReactMessageInput{
+		Session:     session,
+		Chat:        req.Chat,
+		MessageID:   id,
+		Participant: req.Participant,
+		Emoji:       req.Emoji,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "react failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ReactMessageResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
+
+func (h *Handler) MessageReactions(c *gin.Context) {
+	session := c.Param("session")
+	id := c.Param("id")
+	chat := c.Query("chat")
+	if session == "" || id == "" || chat == "" {
+		c.JSON(400, gin.H{
+			"error": "session and id params and chat query are required",
+		})
+		return
+	}
+
+	items, err := h.reactUC.List(c.Request.Context(), session, chat, id)
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "list reactions failed", "detail": err.Error()})
+		return
+	}
+
+	reactions := make([]ReactionItemResponse, 0, len(items))
+	for _, item := range items {
+		reactions = append(reactions, ReactionItemResponse{
+			Sender:    item.Sender,
+			Emoji:     item.Emoji,
+			Timestamp: item.Timestamp.Unix(),
+		})
+	}
+
+	c.JSON(http.StatusOK, MessageReactionsResponse{
+		MessageID: id,
+		Reactions: reactions,
+	})
+}
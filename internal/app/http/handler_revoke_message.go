@@ -0,0 +1,47 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) RevokeMessage(c *gin.Context) {
+	session := c.Param("session")
+	chat := c.Param("chat")
+	id := c.Param("id")
+	if session == "" || chat == "" || id == "" {
+		c.JSON(400, gin.H{
+			"error": "session, chat and id params are required",
+		})
+		return
+	}
+
+	out, err := h.revokeUC.Execute(c.Request.Context(), usecase.RevokeMessageInput{
+		Session:     session,
+		Chat:        chat,
+		MessageID:   id,
+		Participant: c.Query("participant"),
+	})
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrNotGroupAdmin) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": "revoke message failed", "detail": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if !out.Accepted {
+		status = http.StatusBadGateway
+	}
+	c.JSON(status, RevokeMessageResponse{
+		Status:    out.Status,
+		Accepted:  out.Accepted,
+		MessageID: out.MessageID,
+		Detail:    out.Detail,
+	})
+}
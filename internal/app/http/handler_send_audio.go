@@ -0,0 +1,22 @@
+package http
+
+import (
+	"context"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendAudio(c *gin.Context) {
+	var req SendAudioRequest
+	h.sendMedia(c, &req, &req.SendMediaRequest, "audio", func(ctx context.Context, session string, src usecase.MediaSource) (*usecase.SendMediaOutput, error) {
+		return h.sendAudUC.Execute(ctx, usecase.SendAudioInput{
+			Session:     session,
+			To:          req.To,
+			PTT:         req.PTT,
+			Media:       src,
+			ViewOnce:    req.ViewOnce,
+			SendOptions: req.options(),
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/vcard"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendContact(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	var req SendContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+	if req.To == "" || len(req.Contacts) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to and contacts are required"})
+		return
+	}
+
+	contacts := make([]vcard.Contact, 0, len(req.Contacts))
+	for _, item := range req.Contacts {
+		phones := make([]vcard.Phone, 0, len(item.Phones))
+		for _, p := range item.Phones {
+			phones = append(phones, vcard.Phone{Number: p.Number, Type: p.Type})
+		}
+		contacts = append(contacts, vcard.Contact{
+			Name:   item.Name,
+			Phones: phones,
+			Email:  item.Email,
+			Org:    item.Org,
+		})
+	}
+
+	out, err := h.contactUC.Execute(c.Request.Context(), usecase.SendContactInput{
+		Session:     session,
+		To:          req.To,
+		Contacts:    contacts,
+		SendOptions: req.options(),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "send contact failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SendContactResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
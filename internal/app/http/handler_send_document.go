@@ -0,0 +1,21 @@
+package http
+
+import (
+	"context"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendDocument(c *gin.Context) {
+	var req SendDocumentRequest
+	h.sendMedia(c, &req, &req.SendMediaRequest, "document", func(ctx context.Context, session string, src usecase.MediaSource) (*usecase.SendMediaOutput, error) {
+		return h.sendDocUC.Execute(ctx, usecase.SendDocumentInput{
+			Session:     session,
+			To:          req.To,
+			Caption:     req.Caption,
+			Media:       src,
+			SendOptions: req.options(),
+		})
+	})
+}
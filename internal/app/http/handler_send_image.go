@@ -0,0 +1,22 @@
+package http
+
+import (
+	"context"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendImage(c *gin.Context) {
+	var req SendImageRequest
+	h.sendMedia(c, &req, &req.SendMediaRequest, "image", func(ctx context.Context, session string, src usecase.MediaSource) (*usecase.SendMediaOutput, error) {
+		return h.sendImgUC.Execute(ctx, usecase.SendImageInput{
+			Session:     session,
+			To:          req.To,
+			Caption:     req.Caption,
+			Media:       src,
+			ViewOnce:    req.ViewOnce,
+			SendOptions: req.options(),
+		})
+	})
+}
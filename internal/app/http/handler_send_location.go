@@ -0,0 +1,156 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendLocation(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	var req SendLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+	if req.To == "" || req.Latitude == nil || req.Longitude == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to, latitude and longitude are required"})
+		return
+	}
+
+	out, err := h.locUC.Execute(c.Request.Context(), usecase.SendLocationInput{
+		Session:     session,
+		To:          req.To,
+		Latitude:    *req.Latitude,
+		Longitude:   *req.Longitude,
+		Name:        req.Name,
+		Address:     req.Address,
+		URL:         req.URL,
+		SendOptions: req.options(),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "send location failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SendLocationResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
+
+func (h *Handler) StartLiveLocation(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	var req StartLiveLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+	if req.To == "" || req.Latitude == nil || req.Longitude == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to, latitude and longitude are required"})
+		return
+	}
+
+	out, err := h.liveUC.Start(c.Request.Context(), usecase.StartLiveLocationInput{
+		Session:        session,
+		To:             req.To,
+		Latitude:       *req.Latitude,
+		Longitude:      *req.Longitude,
+		AccuracyMeters: req.AccuracyMeters,
+		Caption:        req.Caption,
+		Duration:       time.Duration(req.DurationSeconds) * time.Second,
+		Interval:       time.Duration(req.IntervalSeconds) * time.Second,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start live location failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, liveLocationResponse(out))
+}
+
+func (h *Handler) UpdateLiveLocation(c *gin.Context) {
+	session := c.Param("session")
+	id := c.Param("id")
+	if session == "" || id == "" {
+		c.JSON(400, gin.H{
+			"error": "session and id params are required",
+		})
+		return
+	}
+
+	var req UpdateLiveLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+	if req.Latitude == nil || req.Longitude == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "latitude and longitude are required"})
+		return
+	}
+
+	out, err := h.liveUC.Update(c.Request.Context(), usecase.UpdateLiveLocationInput{
+		Session:        session,
+		ID:             id,
+		Latitude:       *req.Latitude,
+		Longitude:      *req.Longitude,
+		AccuracyMeters: req.AccuracyMeters,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "update live location failed", "detail": err.Error()})
+		return
+	}
+	if out == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "live location not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, liveLocationResponse(out))
+}
+
+func (h *Handler) StopLiveLocation(c *gin.Context) {
+	session := c.Param("session")
+	id := c.Param("id")
+	if session == "" || id == "" {
+		c.JSON(400, gin.H{
+			"error": "session and id params are required",
+		})
+		return
+	}
+
+	stopped, err := h.liveUC.Stop(c.Request.Context(), session, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "stop live location failed", "detail": err.Error()})
+		return
+	}
+	if !stopped {
+		c.JSON(http.StatusNotFound, gin.H{"error": "live location not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, LiveLocationResponse{Status: "stopped", ID: id})
+}
+
+func liveLocationResponse(out *usecase.LiveLocationOutput) LiveLocationResponse {
+	return LiveLocationResponse{
+		Status:    out.Status,
+		ID:        out.ID,
+		ExpiresIn: int(time.Until(out.ExpiresAt).Seconds()),
+	}
+}
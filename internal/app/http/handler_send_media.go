@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type mediaSendFunc func(ctx context.Context, session string, src usecase.MediaSource) (*usecase.SendMediaOutput, error)
+
+// sendMedia is the request flow shared by every media endpoint: bind req,
+// resolve its media, hand both to send and render the result.
+func (h *Handler) sendMedia(c *gin.Context, req any, base *SendMediaRequest, kind string, send mediaSendFunc) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	src, err := bindMediaRequest(c, req, base)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "detail": err.Error()})
+		return
+	}
+	if base.To == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to is required"})
+		return
+	}
+
+	out, err := send(c.Request.Context(), session, src)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "send " + kind + " failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SendMediaResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
+
+// bindMediaRequest binds req from either a JSON body or a multipart form and
+// resolves the media it carries: an uploaded "file" part, a base64 (or data
+// URI) "file" field, or a remote "url".
+func bindMediaRequest(c *gin.Context, req any, base *SendMediaRequest) (usecase.MediaSource, error) {
+	if err := c.ShouldBind(req); err != nil {
+		return usecase.MediaSource{}, fmt.Errorf("invalid request: %w", err)
+	}
+	if err := bindFormOptions(c, &base.SendOptionsRequest); err != nil {
+		return usecase.MediaSource{}, err
+	}
+
+	src := usecase.MediaSource{
+		URL:      strings.TrimSpace(base.URL),
+		Filename: strings.TrimSpace(base.Filename),
+		Mimetype: strings.TrimSpace(base.Mimetype),
+	}
+
+	part, err := readMediaPart(c, "file", base.File)
+	if err != nil {
+		return src, err
+	}
+	if part == nil {
+		if src.URL == "" {
+			return src, fmt.Errorf("file or url is required")
+		}
+		return src, nil
+	}
+
+	src.Data = part.data
+	if src.Filename == "" {
+		src.Filename = part.filename
+	}
+	if src.Mimetype == "" {
+		src.Mimetype = part.mimetype
+	}
+	return src, nil
+}
+
+type mediaPart struct {
+	data     []byte
+	filename string
+	mimetype string
+}
+
+// readMediaPart returns the multipart file named field, or else the decoded
+// base64 value encoded. It returns nil when neither was sent.
+func readMediaPart(c *gin.Context, field, encoded string) (*mediaPart, error) {
+	if fh, err := c.FormFile(field); err == nil {
+		if fh.Size > usecase.MaxMediaBytes {
+			return nil, fmt.Errorf("%s exceeds %d bytes", field, usecase.MaxMediaBytes)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", field, err)
+		}
+		defer f.Close()
+
+		data, err := io.ReadAll(io.LimitReader(f, usecase.MaxMediaBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", field, err)
+		}
+		return &mediaPart{
+			data:     data,
+			filename: fh.Filename,
+			mimetype: fh.Header.Get("Content-Type"),
+		}, nil
+	}
+
+	if encoded == "" {
+		return nil, nil
+	}
+
+	data, mimetype, err := decodeBase64Media(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 %s: %w", field, err)
+	}
+	return &mediaPart{data: data, mimetype: mimetype}, nil
+}
+
+// decodeBase64Media accepts plain base64 or a data URI such as
+// "data:image/png;base64,....", returning the mimetype from the latter.
+func decodeBase64Media(raw string) ([]byte, string, error) {
+	raw = strings.TrimSpace(raw)
+	mimetype := ""
+
+	if strings.HasPrefix(raw, "data:") {
+		header, payload, ok := strings.Cut(raw, ",")
+		if !ok || !strings.HasSuffix(header, ";base64") {
+			return nil, "", fmt.Errorf("data uri must be base64 encoded")
+		}
+		mimetype = strings.TrimSuffix(strings.TrimPrefix(header, "data:"), ";base64")
+		raw = payload
+	}
+
+	data, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return data, mimetype, nil
+}
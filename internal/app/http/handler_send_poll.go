@@ -0,0 +1,90 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendPoll(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	var req SendPollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+	if req.To == "" || req.Question == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to and question are required"})
+		return
+	}
+
+	out, err := h.pollUC.Execute(c.Request.Context(), usecase.SendPollInput{
+		Session:     session,
+		To:          req.To,
+		Question:    req.Question,
+		Options:     req.Options,
+		MultiSelect: req.MultiSelect,
+		SendOptions: req.options(),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "send poll failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SendPollResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
+
+func (h *Handler) PollResults(c *gin.Context) {
+	session := c.Param("session")
+	id := c.Param("id")
+	chat := c.Query("chat")
+	if session == "" || id == "" || chat == "" {
+		c.JSON(400, gin.H{
+			"error": "session and id params and chat query are required",
+		})
+		return
+	}
+
+	tally, err := h.pollUC.Tally(c.Request.Context(), session, chat, id)
+	if err != nil {
+		if errors.Is(err, usecase.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+		if errors.Is(err, usecase.ErrMessageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "poll not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "poll results failed", "detail": err.Error()})
+		return
+	}
+
+	options := make([]PollOptionResponse, 0, len(tally.Options))
+	for _, option := range tally.Options {
+		options = append(options, PollOptionResponse{
+			Name:   option.Name,
+			Votes:  option.Votes,
+			Voters: option.Voters,
+		})
+	}
+
+	c.JSON(http.StatusOK, PollTallyResponse{
+		MessageID:   tally.MessageID,
+		Question:    tally.Question,
+		MultiSelect: tally.MultiSelect,
+		Options:     options,
+		UpdatedAt:   tally.UpdatedAt.Unix(),
+	})
+}
@@ -0,0 +1,21 @@
+package http
+
+import (
+	"context"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendSticker(c *gin.Context) {
+	var req SendStickerRequest
+	h.sendMedia(c, &req, &req.SendMediaRequest, "sticker", func(ctx context.Context, session string, src usecase.MediaSource) (*usecase.SendMediaOutput, error) {
+		return h.sendStkUC.Execute(ctx, usecase.SendStickerInput{
+			Session:     session,
+			To:          req.To,
+			Convert:     req.Convert,
+			Media:       src,
+			SendOptions: req.options(),
+		})
+	})
+}
@@ -29,10 +29,33 @@ func (h *Handler) SendText(c *gin.Context) {
 		return
 	}
 
+	var preview *usecase.LinkPreview
+	if req.Preview != nil {
+		preview = &usecase.LinkPreview{
+			URL:          req.Preview.URL,
+			Title:        req.Preview.Title,
+			Description:  req.Preview.Description,
+			ThumbnailURL: req.Preview.ThumbnailURL,
+		}
+		if req.Preview.Thumbnail != "" {
+			thumb, _, err := decodeBase64Media(req.Preview.Thumbnail)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid preview thumbnail", "detail": err.Error()})
+				return
+			}
+			preview.Thumbnail = thumb
+		}
+	}
+
 	out, err := h.sendUC.Execute(c.Request.Context(), usecase.SendTextInput{
-		Session: session,
-		To:      req.To,
-		Message: req.Message,
+		Session:     session,
+		To:          req.To,
+		Message:     req.Message,
+		Mentions:    req.Mentions,
+		MentionAll:  req.MentionAll,
+		LinkPreview: req.LinkPreview,
+		Preview:     preview,
+		SendOptions: req.options(),
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "send text failed", "detail": err.Error()})
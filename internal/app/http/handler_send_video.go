@@ -0,0 +1,32 @@
+package http
+
+import (
+	"context"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SendVideo(c *gin.Context) {
+	var req SendVideoRequest
+	h.sendMedia(c, &req, &req.SendMediaRequest, "video", func(ctx context.Context, session string, src usecase.MediaSource) (*usecase.SendMediaOutput, error) {
+		thumb, err := readMediaPart(c, "thumbnail", req.Thumbnail)
+		if err != nil {
+			return nil, err
+		}
+
+		in := usecase.SendVideoInput{
+			Session:     session,
+			To:          req.To,
+			Caption:     req.Caption,
+			GifPlayback: req.GifPlayback,
+			Media:       src,
+			ViewOnce:    req.ViewOnce,
+			SendOptions: req.options(),
+		}
+		if thumb != nil {
+			in.Thumbnail = thumb.data
+		}
+		return h.sendVidUC.Execute(ctx, in)
+	})
+}
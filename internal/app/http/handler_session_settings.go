@@ -0,0 +1,84 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SessionSettings(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	settings, err := h.settingsUC.Get(c.Request.Context(), session)
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "get settings failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionSettingsResponse(settings))
+}
+
+func (h *Handler) UpdateSessionSettings(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	var req SessionSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+
+	settings, err := h.settingsUC.Update(c.Request.Context(), usecase.UpdateSettingsInput{
+		Session:       session,
+		AutoRead:      req.AutoRead,
+		WebhookURL:    req.WebhookURL,
+		WebhookEvents: req.WebhookEvents,
+
+		WebhookSecret:         req.WebhookSecret,
+		GenerateWebhookSecret: req.GenerateWebhookSecret,
+		RetirePreviousSecret:  req.RetirePreviousSecret,
+	})
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "update settings failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionSettingsResponse(settings))
+}
+
+func sessionSettingsResponse(settings *usecase.SessionSettingsOutput) SessionSettingsResponse {
+	resp := SessionSettingsResponse{
+		AutoRead:      settings.AutoRead,
+		WebhookURL:    settings.WebhookURL,
+		WebhookEvents: settings.WebhookEvents,
+
+		WebhookSigned:   settings.WebhookSigned,
+		WebhookRotating: settings.WebhookRotating,
+		WebhookSecret:   settings.WebhookSecret,
+	}
+	if resp.WebhookEvents == nil {
+		resp.WebhookEvents = []string{}
+	}
+	return resp
+}
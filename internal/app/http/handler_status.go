@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) PostTextStatus(c *gin.Context) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	var req PostTextStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid json", "detail": err.Error()})
+		return
+	}
+	if req.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "text is required"})
+		return
+	}
+
+	out, err := h.statusUC.PostText(c.Request.Context(), usecase.PostTextStatusInput{
+		Session:         session,
+		Text:            req.Text,
+		BackgroundColor: req.BackgroundColor,
+		Font:            req.Font,
+		Recipients:      req.Recipients,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "post status failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, StatusResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
+
+func (h *Handler) PostImageStatus(c *gin.Context) {
+	h.postMediaStatus(c, h.statusUC.PostImage)
+}
+
+func (h *Handler) PostVideoStatus(c *gin.Context) {
+	h.postMediaStatus(c, h.statusUC.PostVideo)
+}
+
+func (h *Handler) postMediaStatus(c *gin.Context, post func(context.Context, usecase.PostMediaStatusInput) (*usecase.StatusOutput, error)) {
+	session := c.Param("session")
+	if session == "" {
+		c.JSON(400, gin.H{
+			"error": "session param is required",
+		})
+		return
+	}
+
+	var req PostMediaStatusRequest
+	src, err := bindMediaRequest(c, &req, &req.SendMediaRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "detail": err.Error()})
+		return
+	}
+	if field := unsupportedStatusField(req.SendMediaRequest); field != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": field + " is not supported for status posts"})
+		return
+	}
+
+	out, err := post(c.Request.Context(), usecase.PostMediaStatusInput{
+		Session:    session,
+		Caption:    req.Caption,
+		Media:      src,
+		Recipients: req.Recipients,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "post status failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, StatusResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
+
+func (h *Handler) DeleteStatus(c *gin.Context) {
+	session := c.Param("session")
+	id := c.Param("id")
+	if session == "" || id == "" {
+		c.JSON(400, gin.H{
+			"error": "session and id params are required",
+		})
+		return
+	}
+
+	out, err := h.statusUC.Delete(c.Request.Context(), session, id)
+	if err != nil {
+		if errors.Is(err, usecase.ErrMessageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "status not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "delete status failed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, StatusResponse{
+		Status:    out.Status,
+		MessageID: out.MessageID,
+	})
+}
+
+// unsupportedStatusField names the first field of the shared media request
+// that a status post cannot honour, or returns "".
+func unsupportedStatusField(req SendMediaRequest) string {
+	switch {
+	case req.To != "":
+		return "to"
+	case req.ReplyTo != nil:
+		return "reply_to"
+	case req.Async:
+		return "async"
+	case req.SimulateTyping:
+		return "simulate_typing"
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeadLetters lists webhook deliveries that were given up on, optionally
+// limited to ?session=.
+func (h *Handler) DeadLetters(c *gin.Context) {
+	count, err := queryInt(c, "count")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	letters, err := h.deadLetterUC.List(c.Request.Context(), c.Query("session"), count)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "list dead letters failed", "detail": err.Error()})
+		return
+	}
+
+	resp := DeadLettersResponse{DeadLetters: make([]DeadLetterResponse, 0, len(letters))}
+	for _, d := range letters {
+		resp.DeadLetters = append(resp.DeadLetters, DeadLetterResponse{
+			ID:        d.ID,
+			Session:   d.Session,
+			EventType: d.EventType,
+			Payload:   d.Payload,
+			Attempts:  d.Attempts,
+			LastError: d.LastError,
+			CreatedAt: d.CreatedAt.Unix(),
+			FailedAt:  d.FailedAt.Unix(),
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ReplayDeadLetters queues every dead letter, or those of ?session=, for
+// delivery again.
+func (h *Handler) ReplayDeadLetters(c *gin.Context) {
+	n, err := h.deadLetterUC.Replay(c.Request.Context(), c.Query("session"), 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "replay dead letters failed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, DeadLettersChangeResponse{Status: "queued", Count: n})
+}
+
+func (h *Handler) ReplayDeadLetter(c *gin.Context) {
+	id, ok := deadLetterID(c)
+	if !ok {
+		return
+	}
+
+	n, err := h.deadLetterUC.Replay(c.Request.Context(), "", id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "replay dead letter failed", "detail": err.Error()})
+		return
+	}
+	if n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "dead letter not found"})
+		return
+	}
+	c.JSON(http.StatusOK, DeadLettersChangeResponse{Status: "queued", Count: n})
+}
+
+// DeleteDeadLetters discards every dead letter, or those of ?session=.
+func (h *Handler) DeleteDeadLetters(c *gin.Context) {
+	n, err := h.deadLetterUC.Delete(c.Request.Context(), c.Query("session"), 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "delete dead letters failed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, DeadLettersChangeResponse{Status: "deleted", Count: n})
+}
+
+func (h *Handler) DeleteDeadLetter(c *gin.Context) {
+	id, ok := deadLetterID(c)
+	if !ok {
+		return
+	}
+
+	n, err := h.deadLetterUC.Delete(c.Request.Context(), "", id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "delete dead letter failed", "detail": err.Error()})
+		return
+	}
+	if n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "dead letter not found"})
+		return
+	}
+	c.JSON(http.StatusOK, DeadLettersChangeResponse{Status: "deleted", Count: n})
+}
+
+func deadLetterID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dead letter id"})
+		return 0, false
+	}
+	return id, true
+}
@@ -11,12 +11,51 @@ func NewRouter(h *Handler) *gin.Engine {
 	wa := r.Group("/api")
 	wa.POST("/:session/auth/request-code", h.PairCode)
 	wa.POST("/:session/sendText", h.SendText)
+	wa.POST("/:session/sendImage", h.SendImage)
+	wa.POST("/:session/sendDocument", h.SendDocument)
+	wa.POST("/:session/sendAudio", h.SendAudio)
+	wa.POST("/:session/sendVideo", h.SendVideo)
+	wa.POST("/:session/sendSticker", h.SendSticker)
+	wa.POST("/:session/sendLocation", h.SendLocation)
+	wa.POST("/:session/sendContact", h.SendContact)
+	wa.POST("/:session/sendPoll", h.SendPoll)
+	wa.GET("/:session/polls/:id", h.PollResults)
+	wa.POST("/:session/messages/:id/react", h.ReactMessage)
+	wa.GET("/:session/messages/:id/reactions", h.MessageReactions)
+	wa.PATCH("/:session/chats/:chat/messages/:id", h.EditMessage)
+	wa.DELETE("/:session/chats/:chat/messages/:id", h.RevokeMessage)
+	wa.GET("/:session/chats/:chat/disappearing", h.ChatDisappearing)
+	wa.PUT("/:session/chats/:chat/disappearing", h.SetChatDisappearing)
+	wa.GET("/:session/disappearing", h.DefaultDisappearing)
+	wa.PUT("/:session/disappearing", h.SetDefaultDisappearing)
+	wa.POST("/:session/chats/:chat/read", h.MarkRead)
+	wa.POST("/:session/liveLocation", h.StartLiveLocation)
+	wa.PUT("/:session/liveLocation/:id", h.UpdateLiveLocation)
+	wa.DELETE("/:session/liveLocation/:id", h.StopLiveLocation)
+	wa.POST("/:session/status/text", h.PostTextStatus)
+	wa.POST("/:session/status/image", h.PostImageStatus)
+	wa.POST("/:session/status/video", h.PostVideoStatus)
+	wa.DELETE("/:session/status/:id", h.DeleteStatus)
+	wa.GET("/:session/newsletters", h.Newsletters)
+	wa.GET("/:session/newsletters/lookup", h.LookupNewsletter)
+	wa.POST("/:session/newsletters/:jid/follow", h.FollowNewsletter)
+	wa.DELETE("/:session/newsletters/:jid/follow", h.UnfollowNewsletter)
+	wa.GET("/:session/newsletters/:jid/messages", h.NewsletterMessages)
 	wa.GET("/clients", h.Clients)
+	wa.GET("/ws", h.EventsSocket)
+	wa.GET("/webhooks/dead-letters", h.DeadLetters)
+	wa.POST("/webhooks/dead-letters/replay", h.ReplayDeadLetters)
+	wa.POST("/webhooks/dead-letters/:id/replay", h.ReplayDeadLetter)
+	wa.DELETE("/webhooks/dead-letters", h.DeleteDeadLetters)
+	wa.DELETE("/webhooks/dead-letters/:id", h.DeleteDeadLetter)
 
 	sessions := wa.Group("/sessions")
 	sessions.GET("/:session/me", h.Me)
+	sessions.GET("/:session/settings", h.SessionSettings)
+	sessions.PUT("/:session/settings", h.UpdateSessionSettings)
 	sessions.GET("/:session/pair/stream", h.PairStream)
 	sessions.GET("/stream", h.SessionsStream)
+	sessions.GET("/:session/events/stream", h.SessionEventsStream)
 	sessions.DELETE("/:session", h.DeleteSession)
 	sessions.DELETE("/:session/force", h.ForceDeleteSession)
 	sessions.POST("/:session/stop", h.StopSession)
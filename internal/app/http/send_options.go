@@ -0,0 +1,37 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func (r SendOptionsRequest) options() usecase.SendOptions {
+	opts := usecase.SendOptions{
+		SimulateTyping: r.SimulateTyping,
+		Async:          r.Async,
+	}
+	if r.ReplyTo != nil {
+		opts.ReplyTo = &usecase.ReplyTo{
+			MessageID:   r.ReplyTo.MessageID,
+			Participant: r.ReplyTo.Participant,
+			Text:        r.ReplyTo.Text,
+		}
+	}
+	return opts
+}
+
+// bindFormOptions fills the options that multipart forms carry as
+// JSON-encoded fields.
+func bindFormOptions(c *gin.Context, r *SendOptionsRequest) error {
+	if raw := c.PostForm("reply_to"); raw != "" && r.ReplyTo == nil {
+		var reply ReplyToRequest
+		if err := json.Unmarshal([]byte(raw), &reply); err != nil {
+			return fmt.Errorf("invalid reply_to: %w", err)
+		}
+		r.ReplyTo = &reply
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+type DisappearingOutput struct {
+	Chat string
+	// Timer is one of off, 24h, 7d or 90d.
+	Timer   string
+	Seconds int64
+	// Known is false when the gateway has not seen the chat's setting yet.
+	// The timer reported is then the one sends use: the session's default
+	// for a new direct chat, off otherwise.
+	Known     bool
+	SettingAt time.Time
+}
+
+type SetDisappearingInput struct {
+	Session string
+	// Chat is empty to set the session's default for new chats.
+	Chat  string
+	Timer string
+}
+
+type DisappearingUsecase struct {
+	wa *wa.Manager
+}
+
+func NewDisappearingUsecase(waManager *wa.Manager) *DisappearingUsecase {
+	return &DisappearingUsecase{wa: waManager}
+}
+
+// Get returns a chat's timer, or the session's default when chatRaw is empty.
+func (u *DisappearingUsecase) Get(ctx context.Context, session, chatRaw string) (*DisappearingOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := requireSession(u.wa, session); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(chatRaw) == "" {
+		setting, found, err := u.wa.DefaultEphemeral(ctx, session)
+		if err != nil {
+			return nil, err
+		}
+		return disappearingOutput("", setting, found), nil
+	}
+
+	chat, err := parseRecipient(chatRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, session)
+	if err != nil {
+		return nil, err
+	}
+
+	// Groups can be asked directly, so their answer is never stale.
+	if chat.Server == types.GroupServer {
+		if err := refreshGroupEphemeral(ctx, u.wa, session, client, chat); err != nil {
+			return nil, err
+		}
+	}
+
+	setting, found, err := u.wa.ChatEphemeral(ctx, session, chat)
+	if err != nil {
+		return nil, err
+	}
+	out := disappearingOutput(chat.String(), setting, found)
+	if !found {
+		if setting, err = chatTimerFallback(ctx, u.wa, session, chat); err != nil {
+			return nil, err
+		}
+		if setting != nil {
+			out.Timer = timerLabel(setting.Timer)
+			out.Seconds = int64(setting.Timer / time.Second)
+		}
+	}
+	return out, nil
+}
+
+func disappearingOutput(chat string, setting *wa.ChatEphemeral, found bool) *DisappearingOutput {
+	out := &DisappearingOutput{Chat: chat, Timer: timerLabel(0), Known: found}
+	if found {
+		out.Timer = timerLabel(setting.Timer)
+		out.Seconds = int64(setting.Timer / time.Second)
+		out.SettingAt = setting.SettingAt
+	}
+	return out
+}
+
+// Set changes a chat's timer, or the session's default when in.Chat is empty.
+func (u *DisappearingUsecase) Set(ctx context.Context, in SetDisappearingInput) (*DisappearingOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	timer, ok := whatsmeow.ParseDisappearingTimerString(strings.TrimSpace(in.Timer))
+	if !ok {
+		return nil, fmt.Errorf("timer must be one of off, 24h, 7d or 90d")
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &DisappearingOutput{
+		Timer:     timerLabel(timer),
+		Seconds:   int64(timer / time.Second),
+		Known:     true,
+		SettingAt: time.Now(),
+	}
+
+	if strings.TrimSpace(in.Chat) == "" {
+		if err := client.SetDefaultDisappearingTimer(ctx, timer); err != nil {
+			return nil, err
+		}
+		err := u.wa.SaveDefaultEphemeral(ctx, in.Session, wa.ChatEphemeral{Timer: timer, SettingAt: out.SettingAt})
+		if err != nil {
+			log.Printf("store default ephemeral setting: %v", err)
+		}
+		return out, nil
+	}
+
+	chat, err := parseRecipient(in.Chat)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SetDisappearingTimer(ctx, chat, timer, out.SettingAt); err != nil {
+		return nil, err
+	}
+	out.Chat = chat.String()
+
+	err = u.wa.SaveChatEphemeral(ctx, in.Session, chat, wa.ChatEphemeral{Timer: timer, SettingAt: out.SettingAt})
+	if err != nil {
+		log.Printf("store ephemeral setting of %s: %v", chat, err)
+	}
+	return out, nil
+}
+
+// applyExpiration stamps msg with the chat's disappearing timer, as WhatsApp
+// clients do, so it disappears along with the rest of the chat. Groups the
+// gateway knows nothing about yet are looked up once; new direct chats get
+// the session's default timer, which from then on is the chat's own.
+// Reactions and revokes have no ContextInfo to stamp and disappear with the
+// message they target.
+func applyExpiration(ctx context.Context, waManager *wa.Manager, session string, client *whatsmeow.Client, chat types.JID, msg *waE2E.Message) error {
+	switch chat.Server {
+	case types.DefaultUserServer, types.HiddenUserServer, types.GroupServer:
+	default:
+		return nil
+	}
+
+	setting, found, err := waManager.ChatEphemeral(ctx, session, chat)
+	if err != nil {
+		return fmt.Errorf("load ephemeral setting: %w", err)
+	}
+	if !found && chat.Server == types.GroupServer {
+		if err := refreshGroupEphemeral(ctx, waManager, session, client, chat); err != nil {
+			// Not worth failing the send over; the next one tries again.
+			log.Printf("ephemeral setting of %s: %v", chat, err)
+			return nil
+		}
+		if setting, found, err = waManager.ChatEphemeral(ctx, session, chat); err != nil {
+			return fmt.Errorf("load ephemeral setting: %w", err)
+		}
+	}
+	if !found {
+		if setting, err = chatTimerFallback(ctx, waManager, session, chat); err != nil {
+			return err
+		}
+		// Whatever the chat starts with is remembered, as its history may be
+		// trimmed from the message store long before the chat goes quiet.
+		// Off is a guess, dated so any setting actually seen replaces it.
+		saved := wa.ChatEphemeral{SettingAt: time.Unix(0, 0)}
+		if setting != nil {
+			saved = *setting
+		}
+		if err := waManager.SaveChatEphemeral(ctx, session, chat, saved); err != nil {
+			log.Printf("store ephemeral setting of %s: %v", chat, err)
+		}
+	}
+	if setting == nil || setting.Timer == 0 {
+		return nil
+	}
+
+	if ci := contextInfoOf(msg); ci != nil {
+		ci.Expiration = proto.Uint32(uint32(setting.Timer / time.Second))
+		// Group timers are set by the server, which keeps no such timestamp.
+		if chat.Server != types.GroupServer {
+			ci.EphemeralSettingTimestamp = proto.Int64(setting.SettingAt.Unix())
+		}
+	}
+	return nil
+}
+
+// chatTimerFallback is the timer for a chat whose own setting is unknown: the
+// session's default for a new direct chat, which is what WhatsApp starts it
+// with. A chat with messages stored is not new; it has never been seen with
+// a timer, so it has none. It returns nil when there is no timer.
+func chatTimerFallback(ctx context.Context, waManager *wa.Manager, session string, chat types.JID) (*wa.ChatEphemeral, error) {
+	if chat.Server == types.GroupServer {
+		return nil, nil
+	}
+	known, err := waManager.HasChatMessages(ctx, session, chat)
+	if err != nil {
+		return nil, fmt.Errorf("load chat history: %w", err)
+	}
+	if known {
+		return nil, nil
+	}
+	setting, found, err := waManager.DefaultEphemeral(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("load default ephemeral setting: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return setting, nil
+}
+
+func refreshGroupEphemeral(ctx context.Context, waManager *wa.Manager, session string, client *whatsmeow.Client, group types.JID) error {
+	info, err := client.GetGroupInfo(ctx, group)
+	if err != nil {
+		return fmt.Errorf("get group info: %w", err)
+	}
+
+	setting := wa.ChatEphemeral{SettingAt: time.Now()}
+	if info.IsEphemeral {
+		setting.Timer = time.Duration(info.DisappearingTimer) * time.Second
+	}
+	return waManager.SaveChatEphemeral(ctx, session, group, setting)
+}
+
+func timerLabel(timer time.Duration) string {
+	switch timer {
+	case whatsmeow.DisappearingTimerOff:
+		return "off"
+	case whatsmeow.DisappearingTimer24Hours:
+		return "24h"
+	case whatsmeow.DisappearingTimer7Days:
+		return "7d"
+	case whatsmeow.DisappearingTimer90Days:
+		return "90d"
+	}
+	return timer.String()
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// editWindow is how long after sending WhatsApp accepts an edit.
+const editWindow = 15 * time.Minute
+
+var (
+	ErrMessageNotFound    = errors.New("message not found")
+	ErrEditWindowExpired  = fmt.Errorf("messages can only be edited within %s of sending", editWindow)
+	ErrMessageNotEditable = errors.New("only text messages sent by this session can be edited")
+)
+
+type EditMessageInput struct {
+	Session   string
+	Chat      string
+	MessageID string
+	Message   string
+}
+
+type EditMessageOutput struct {
+	Status    string
+	MessageID string
+}
+
+type EditMessageUsecase struct {
+	wa *wa.Manager
+}
+
+func NewEditMessageUsecase(waManager *wa.Manager) *EditMessageUsecase {
+	return &EditMessageUsecase{wa: waManager}
+}
+
+func (u *EditMessageUsecase) Execute(ctx context.Context, in EditMessageInput) (*EditMessageOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.MessageID) == "" {
+		return nil, fmt.Errorf("message id is required")
+	}
+	if strings.TrimSpace(in.Message) == "" {
+		return nil, fmt.Errorf("message is required")
+	}
+
+	chat, err := parseRecipient(in.Chat)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, found, err := u.wa.GetMessage(ctx, in.Session, chat, in.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("load message: %w", err)
+	}
+	if !found {
+		return nil, ErrMessageNotFound
+	}
+	if !stored.FromMe || (stored.Message.Conversation == nil && stored.Message.ExtendedTextMessage == nil) {
+		return nil, ErrMessageNotEditable
+	}
+	if time.Since(stored.Timestamp) > editWindow {
+		return nil, ErrEditWindowExpired
+	}
+
+	content := &waE2E.Message{Conversation: proto.String(in.Message)}
+	// The new text disappears on the chat's timer like the original did.
+	if err := applyExpiration(ctx, u.wa, in.Session, client, stored.Chat, content); err != nil {
+		return nil, err
+	}
+	resp, err := client.SendMessage(ctx, stored.Chat, client.BuildEdit(stored.Chat, in.MessageID, content))
+	if err != nil {
+		return nil, err
+	}
+
+	stored.Message = content
+	if err := u.wa.SaveMessage(ctx, in.Session, *stored); err != nil {
+		log.Printf("store edited message %s: %v", in.MessageID, err)
+	}
+
+	return &EditMessageOutput{Status: "edited", MessageID: resp.ID}, nil
+}
@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+)
+
+// AllEvents subscribes to every session, or to every event type.
+const AllEvents = "*"
+
+// eventBuffer is how many events a listener can fall behind before events
+// are dropped for it, to be fetched from the log once it catches up.
+const eventBuffer = 256
+
+var sessionNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ErrSessionNotFound is returned for a session the gateway does not know.
+var ErrSessionNotFound = wa.ErrSessionNotFound
+
+type EventOutput struct {
+	// ID identifies the event in its session's event log; see wa.EventID.
+	// It is empty for events that were not logged.
+	ID        string
+	Session   string
+	Type      string
+	Timestamp time.Time
+	Data      any
+	// Gap stands for events of Session that were missed and are no longer
+	// logged, so the client has to fetch current state another way. ID is
+	// the last event passed on before them, if any; Type, Timestamp and Data
+	// are unset.
+	Gap bool
+}
+
+// EventFilter is the set of sessions and event types a listener wants. It
+// starts out empty and is safe to change while events are flowing.
+type EventFilter struct {
+	mu   sync.RWMutex
+	subs map[string]map[string]bool
+}
+
+func NewEventFilter() *EventFilter {
+	return &EventFilter{subs: make(map[string]map[string]bool)}
+}
+
+// Subscribe adds every combination of sessions and types. An empty types
+// means every event type; AllEvents stands for every session or type.
+func (f *EventFilter) Subscribe(sessions, types []string) error {
+	if len(sessions) == 0 {
+		return fmt.Errorf("sessions are required")
+	}
+	if len(types) == 0 {
+		types = []string{AllEvents}
+	}
+	for _, s := range sessions {
+		if s != AllEvents && !sessionNameRe.MatchString(s) {
+			return fmt.Errorf("invalid session %q", s)
+		}
+	}
+	for _, t := range types {
+		if t != AllEvents && !slices.Contains(wa.EventTypes, t) {
+			return fmt.Errorf("unknown event type %q, use one of %s", t, strings.Join(wa.EventTypes, ", "))
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range sessions {
+		if f.subs[s] == nil {
+			f.subs[s] = make(map[string]bool)
+		}
+		for _, t := range types {
+			f.subs[s][t] = true
+		}
+	}
+	return nil
+}
+
+// Unsubscribe removes types from sessions, or the sessions altogether when
+// types is empty. Only what was subscribed can be removed: unsubscribing a
+// single session does not carve it out of AllEvents.
+func (f *EventFilter) Unsubscribe(sessions, types []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range sessions {
+		if len(types) == 0 {
+			delete(f.subs, s)
+			continue
+		}
+		for _, t := range types {
+			delete(f.subs[s], t)
+		}
+		if len(f.subs[s]) == 0 {
+			delete(f.subs, s)
+		}
+	}
+}
+
+func (f *EventFilter) Match(session, typ string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, s := range []string{session, AllEvents} {
+		if types := f.subs[s]; types[typ] || types[AllEvents] {
+			return true
+		}
+	}
+	return false
+}
+
+// watches reports whether any events of session are subscribed.
+func (f *EventFilter) watches(session string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.subs[session]) > 0 || len(f.subs[AllEvents]) > 0
+}
+
+// Subscriptions lists the subscribed event types by session.
+func (f *EventFilter) Subscriptions() map[string][]string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	out := make(map[string][]string, len(f.subs))
+	for s, types := range f.subs {
+		list := make([]string, 0, len(types))
+		for t := range types {
+			list = append(list, t)
+		}
+		sort.Strings(list)
+		out[s] = list
+	}
+	return out
+}
+
+type EventsUsecase struct {
+	wa *wa.Manager
+}
+
+func NewEventsUsecase(waManager *wa.Manager) *EventsUsecase {
+	return &EventsUsecase{wa: waManager}
+}
+
+// Listen streams the live events filter matches until ctx is done, then
+// closes the channel. Events dropped for a listener that does not keep up
+// are fetched from the log once it does; a gap event stands for those no
+// longer logged.
+func (u *EventsUsecase) Listen(ctx context.Context, filter *EventFilter) <-chan EventOutput {
+	events, unsubscribe := u.wa.Subscribe(eventBuffer)
+	return u.forward(ctx, events, unsubscribe, filter, make(map[string]wa.EventID))
+}
+
+// forward passes on the events filter matches, each once and in order.
+// last holds the last event passed on of each session.
+func (u *EventsUsecase) forward(ctx context.Context, events <-chan wa.Event, unsubscribe func(), filter *EventFilter, last map[string]wa.EventID) <-chan EventOutput {
+	out := make(chan EventOutput)
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt := <-events:
+				if !filter.watches(evt.Session) {
+					continue
+				}
+				for _, o := range u.catchUp(ctx, last, evt) {
+					if !o.Gap && !filter.Match(o.Session, o.Type) {
+						continue
+					}
+					select {
+					case out <- o:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// catchUp returns what to pass on for evt: nothing when it was passed on
+// already, otherwise the logged events between the last one passed on and
+// evt, then evt. A session's first event sets where it is followed from; the
+// first of a new log, once the session is deleted and paired again, is
+// followed from the log's start.
+func (u *EventsUsecase) catchUp(ctx context.Context, last map[string]wa.EventID, evt wa.Event) []EventOutput {
+	if evt.ID.IsZero() {
+		return []EventOutput{eventOutput(evt)}
+	}
+
+	prev, ok := last[evt.Session]
+	if ok && prev.Epoch != evt.ID.Epoch {
+		prev = wa.EventID{Epoch: evt.ID.Epoch}
+	}
+	switch {
+	case ok && evt.ID.Seq <= prev.Seq:
+		return nil
+	case !ok || evt.ID.Seq == prev.Seq+1:
+		last[evt.Session] = evt.ID
+		return []EventOutput{eventOutput(evt)}
+	}
+
+	missed, complete, err := u.wa.EventsSince(ctx, evt.Session, prev)
+	if err != nil {
+		log.Printf("catch up on events of %s: %v", evt.Session, err)
+		missed, complete = nil, false
+	}
+
+	var out []EventOutput
+	if !complete {
+		out = append(out, EventOutput{ID: prev.String(), Session: evt.Session, Gap: true})
+	}
+	for _, m := range missed {
+		if m.ID.Epoch == evt.ID.Epoch && m.ID.Seq < evt.ID.Seq {
+			out = append(out, eventOutput(m))
+		}
+	}
+	last[evt.Session] = evt.ID
+	return append(out, eventOutput(evt))
+}
+
+// EventStream is a session's event feed, resumed after the last event a
+// client saw.
+type EventStream struct {
+	// Missed are the logged events after the client's last one, oldest
+	// first. Live carries only events that come after them.
+	Missed []EventOutput
+	// Complete is false when some missed events are no longer logged, so
+	// the client has to fetch current state another way.
+	Complete bool
+	Live     <-chan EventOutput
+}
+
+// Stream follows session's events until ctx is done, starting with those
+// after lastID when the client is resuming.
+func (u *EventsUsecase) Stream(ctx context.Context, session string, lastID string) (*EventStream, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var after wa.EventID
+	if lastID != "" {
+		var err error
+		if after, err = wa.ParseEventID(lastID); err != nil {
+			return nil, fmt.Errorf("invalid last event id: %w", err)
+		}
+	}
+
+	if err := requireSession(u.wa, session); err != nil {
+		return nil, err
+	}
+
+	filter := NewEventFilter()
+	if err := filter.Subscribe([]string{session}, nil); err != nil {
+		return nil, err
+	}
+
+	// Listening starts before the log is read so nothing falls in between;
+	// events found in both are passed on once.
+	events, unsubscribe := u.wa.Subscribe(eventBuffer)
+	last := make(map[string]wa.EventID)
+
+	out := &EventStream{Complete: true}
+	if !after.IsZero() {
+		missed, complete, err := u.wa.EventsSince(ctx, session, after)
+		if err != nil {
+			unsubscribe()
+			return nil, fmt.Errorf("load event log: %w", err)
+		}
+		out.Complete = complete
+		for _, evt := range missed {
+			out.Missed = append(out.Missed, eventOutput(evt))
+		}
+
+		switch {
+		case len(missed) > 0:
+			last[session] = missed[len(missed)-1].ID
+		case complete:
+			last[session] = after
+		}
+	}
+
+	out.Live = u.forward(ctx, events, unsubscribe, filter, last)
+	return out, nil
+}
+
+func eventOutput(evt wa.Event) EventOutput {
+	return EventOutput{ID: evt.ID.String(), Session: evt.Session, Type: evt.Type, Timestamp: evt.Timestamp, Data: evt.Data}
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	walog "go.mau.fi/whatsmeow/util/log"
+)
+
+func TestEventFilterMatch(t *testing.T) {
+	type sub struct{ sessions, types []string }
+	tests := []struct {
+		name        string
+		subscribe   []sub
+		unsubscribe []sub
+		session     string
+		typ         string
+		want        bool
+	}{
+		{
+			name:    "nothing subscribed",
+			session: "s1", typ: wa.EventMessage,
+			want: false,
+		},
+		{
+			name:      "session and type",
+			subscribe: []sub{{[]string{"s1"}, []string{wa.EventMessage}}},
+			session:   "s1", typ: wa.EventMessage,
+			want: true,
+		},
+		{
+			name:      "other type",
+			subscribe: []sub{{[]string{"s1"}, []string{wa.EventMessage}}},
+			session:   "s1", typ: wa.EventReceipt,
+			want: false,
+		},
+		{
+			name:      "other session",
+			subscribe: []sub{{[]string{"s1"}, []string{wa.EventMessage}}},
+			session:   "s2", typ: wa.EventMessage,
+			want: false,
+		},
+		{
+			name:      "no types means all",
+			subscribe: []sub{{[]string{"s1"}, nil}},
+			session:   "s1", typ: wa.EventPresence,
+			want: true,
+		},
+		{
+			name:      "wildcard type",
+			subscribe: []sub{{[]string{"s1"}, []string{AllEvents}}},
+			session:   "s1", typ: wa.EventReaction,
+			want: true,
+		},
+		{
+			name:      "wildcard session",
+			subscribe: []sub{{[]string{AllEvents}, []string{wa.EventMessage}}},
+			session:   "s2", typ: wa.EventMessage,
+			want: true,
+		},
+		{
+			name:      "wildcard session, other type",
+			subscribe: []sub{{[]string{AllEvents}, []string{wa.EventMessage}}},
+			session:   "s2", typ: wa.EventReceipt,
+			want: false,
+		},
+		{
+			name:        "type unsubscribed",
+			subscribe:   []sub{{[]string{"s1"}, []string{wa.EventMessage, wa.EventReceipt}}},
+			unsubscribe: []sub{{[]string{"s1"}, []string{wa.EventMessage}}},
+			session:     "s1", typ: wa.EventMessage,
+			want: false,
+		},
+		{
+			name:        "other type kept",
+			subscribe:   []sub{{[]string{"s1"}, []string{wa.EventMessage, wa.EventReceipt}}},
+			unsubscribe: []sub{{[]string{"s1"}, []string{wa.EventMessage}}},
+			session:     "s1", typ: wa.EventReceipt,
+			want: true,
+		},
+		{
+			name:        "session unsubscribed",
+			subscribe:   []sub{{[]string{"s1", "s2"}, nil}},
+			unsubscribe: []sub{{[]string{"s1"}, nil}},
+			session:     "s1", typ: wa.EventMessage,
+			want: false,
+		},
+		{
+			name:        "other session kept",
+			subscribe:   []sub{{[]string{"s1", "s2"}, nil}},
+			unsubscribe: []sub{{[]string{"s1"}, nil}},
+			session:     "s2", typ: wa.EventMessage,
+			want: true,
+		},
+		{
+			name:        "session not carved out of wildcard",
+			subscribe:   []sub{{[]string{AllEvents}, nil}},
+			unsubscribe: []sub{{[]string{"s1"}, nil}},
+			session:     "s1", typ: wa.EventMessage,
+			want: true,
+		},
+		{
+			name:        "type not carved out of wildcard",
+			subscribe:   []sub{{[]string{"s1"}, nil}},
+			unsubscribe: []sub{{[]string{"s1"}, []string{wa.EventMessage}}},
+			session:     "s1", typ: wa.EventMessage,
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewEventFilter()
+			for _, s := range tt.subscribe {
+				if err := f.Subscribe(s.sessions, s.types); err != nil {
+					t.Fatalf("Subscribe(%v, %v): %v", s.sessions, s.types, err)
+				}
+			}
+			for _, s := range tt.unsubscribe {
+				f.Unsubscribe(s.sessions, s.types)
+			}
+			if got := f.Match(tt.session, tt.typ); got != tt.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tt.session, tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventFilterSubscribeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions []string
+		types    []string
+	}{
+		{"no sessions", nil, []string{wa.EventMessage}},
+		{"invalid session", []string{"s1", "bad session"}, nil},
+		{"path in session", []string{"../s1"}, nil},
+		{"unknown type", []string{"s1"}, []string{wa.EventMessage, "typing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewEventFilter()
+			if err := f.Subscribe(tt.sessions, tt.types); err == nil {
+				t.Fatalf("Subscribe(%v, %v) succeeded, want an error", tt.sessions, tt.types)
+			}
+			if got := f.Subscriptions(); len(got) != 0 {
+				t.Errorf("failed Subscribe left subscriptions %v", got)
+			}
+		})
+	}
+}
+
+func TestEventFilterSubscriptions(t *testing.T) {
+	f := NewEventFilter()
+	if err := f.Subscribe([]string{"s1"}, []string{wa.EventReceipt, wa.EventMessage}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Subscribe([]string{"s1", AllEvents}, []string{wa.EventConnection}); err != nil {
+		t.Fatal(err)
+	}
+	f.Unsubscribe([]string{"s2"}, nil)
+
+	want := map[string][]string{
+		"s1":      {wa.EventConnection, wa.EventMessage, wa.EventReceipt},
+		AllEvents: {wa.EventConnection},
+	}
+	if got := f.Subscriptions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Subscriptions() = %v, want %v", got, want)
+	}
+}
+
+func TestEventsCatchUp(t *testing.T) {
+	ctx := context.Background()
+	m := wa.NewManager(t.TempDir(), walog.Noop)
+	// Creating the client creates the session's store, and with it its
+	// event log.
+	if _, err := m.CreateOrGetClientBySession("s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	events, unsubscribe := m.Subscribe(8)
+	defer unsubscribe()
+	var logged []wa.Event
+	for i := 0; i < 5; i++ {
+		if err := m.UpdatePairingFailure("s1", "failed", time.Now(), 0); err != nil {
+			t.Fatal(err)
+		}
+		logged = append(logged, <-events)
+	}
+	stale := wa.Event{Session: "s1", Type: wa.EventPairing, ID: wa.EventID{Epoch: "STALE", Seq: 5}}
+
+	tests := []struct {
+		name     string
+		last     *wa.EventID
+		evt      wa.Event
+		want     []string
+		wantLast wa.EventID
+	}{
+		{"first of session", nil, logged[3], []string{logged[3].ID.String()}, logged[3].ID},
+		{"next", &logged[0].ID, logged[1], []string{logged[1].ID.String()}, logged[1].ID},
+		{"already passed on", &logged[3].ID, logged[1], nil, logged[3].ID},
+		{"dropped in between", &logged[0].ID, logged[4], []string{
+			logged[1].ID.String(), logged[2].ID.String(), logged[3].ID.String(), logged[4].ID.String(),
+		}, logged[4].ID},
+		{"new log", &wa.EventID{Epoch: "OLD", Seq: 3}, logged[2], []string{
+			logged[0].ID.String(), logged[1].ID.String(), logged[2].ID.String(),
+		}, logged[2].ID},
+		{"no longer logged", &wa.EventID{Epoch: "STALE", Seq: 2}, stale, []string{"gap STALE-2", "STALE-5"}, stale.ID},
+		{"not logged", &logged[0].ID, wa.Event{Session: "s1", Type: wa.EventPairing}, []string{""}, logged[0].ID},
+	}
+
+	u := NewEventsUsecase(m)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last := make(map[string]wa.EventID)
+			if tt.last != nil {
+				last["s1"] = *tt.last
+			}
+
+			var got []string
+			for _, o := range u.catchUp(ctx, last, tt.evt) {
+				if o.Gap {
+					got = append(got, "gap "+o.ID)
+					continue
+				}
+				got = append(got, o.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("catchUp passed on %q, want %q", got, tt.want)
+			}
+			if last["s1"] != tt.wantLast {
+				t.Errorf("last is %v, want %v", last["s1"], tt.wantLast)
+			}
+		})
+	}
+}
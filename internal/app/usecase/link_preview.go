@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/linkpreview"
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/media"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	previewFetchTimeout  = 5 * time.Second
+	previewMaxHTMLBytes  = 512 << 10
+	previewMaxImageBytes = 2 << 20
+	previewThumbnailSize = 160
+	previewCacheTTL      = 30 * time.Minute
+	previewCacheSize     = 256
+)
+
+// LinkPreview is the preview shown under a URL in a text message. Fields left
+// empty are filled from the page when the preview is fetched.
+type LinkPreview struct {
+	// URL is the link being previewed. Defaults to the first URL in the text.
+	URL         string
+	Title       string
+	Description string
+	// Thumbnail is an image to show with the preview; ThumbnailURL is
+	// downloaded when it is empty.
+	Thumbnail    []byte
+	ThumbnailURL string
+}
+
+// linkPreviewer fetches pages named in message text, so its client only
+// reaches public addresses.
+type linkPreviewer struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedPreview
+}
+
+// cachedPreview also records failed fetches, as a nil preview, so a broken
+// link is not fetched again on every send.
+type cachedPreview struct {
+	preview *LinkPreview
+	expires time.Time
+}
+
+func newLinkPreviewer() *linkPreviewer {
+	return &linkPreviewer{
+		client: newPublicHTTPClient(previewFetchTimeout),
+		cache:  make(map[string]cachedPreview),
+	}
+}
+
+// apply turns msg into an ExtendedTextMessage with a preview for the URL in
+// text. given overrides what is fetched; with fetch false only given is used.
+// Previews are best effort: when nothing can be found, msg is left as is.
+func (p *linkPreviewer) apply(ctx context.Context, msg *waE2E.Message, text string, given *LinkPreview, fetch bool) {
+	preview := LinkPreview{}
+	if given != nil {
+		preview = *given
+	}
+	preview.URL = strings.TrimSpace(preview.URL)
+	if preview.URL == "" {
+		found, ok := linkpreview.FindURL(text)
+		if !ok {
+			return
+		}
+		preview.URL = found
+	}
+
+	if fetch {
+		if fetched := p.lookup(ctx, preview.URL); fetched != nil {
+			if preview.Title == "" {
+				preview.Title = fetched.Title
+			}
+			if preview.Description == "" {
+				preview.Description = fetched.Description
+			}
+			if len(preview.Thumbnail) == 0 && preview.ThumbnailURL == "" {
+				preview.Thumbnail = fetched.Thumbnail
+			}
+		}
+	}
+	switch {
+	case given != nil && len(given.Thumbnail) > 0:
+		preview.Thumbnail = previewThumbnail(given.Thumbnail)
+	case preview.ThumbnailURL != "":
+		preview.Thumbnail, _ = p.thumbnail(ctx, preview.ThumbnailURL)
+	}
+
+	if preview.Title == "" && preview.Description == "" && len(preview.Thumbnail) == 0 {
+		return
+	}
+
+	contextInfoOf(msg)
+	ext := msg.ExtendedTextMessage
+	ext.MatchedText = proto.String(preview.URL)
+	ext.PreviewType = waE2E.ExtendedTextMessage_NONE.Enum()
+	if preview.Title != "" {
+		ext.Title = proto.String(preview.Title)
+	}
+	if preview.Description != "" {
+		ext.Description = proto.String(preview.Description)
+	}
+	if len(preview.Thumbnail) > 0 {
+		ext.JPEGThumbnail = preview.Thumbnail
+	}
+}
+
+// lookup returns the preview of rawURL from the cache or the page itself.
+func (p *linkPreviewer) lookup(ctx context.Context, rawURL string) *LinkPreview {
+	now := time.Now()
+	p.mu.Lock()
+	if cached, ok := p.cache[rawURL]; ok && now.Before(cached.expires) {
+		p.mu.Unlock()
+		return cached.preview
+	}
+	p.mu.Unlock()
+
+	preview, err := p.fetch(ctx, rawURL)
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up; that says nothing about the link.
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.cache) >= previewCacheSize {
+		for key, cached := range p.cache {
+			if now.After(cached.expires) {
+				delete(p.cache, key)
+			}
+		}
+		for key := range p.cache {
+			if len(p.cache) < previewCacheSize {
+				break
+			}
+			delete(p.cache, key)
+		}
+	}
+	p.cache[rawURL] = cachedPreview{preview: preview, expires: now.Add(previewCacheTTL)}
+	return preview
+}
+
+func (p *linkPreviewer) fetch(ctx context.Context, rawURL string) (*LinkPreview, error) {
+	resp, err := openURL(ctx, p.client, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return nil, fmt.Errorf("not an html page: %s", ct)
+	}
+
+	// Only the head matters, which comes first; a truncated page is fine.
+	meta := linkpreview.Parse(io.LimitReader(resp.Body, previewMaxHTMLBytes), resp.Request.URL)
+
+	preview := &LinkPreview{
+		URL:         rawURL,
+		Title:       meta.Title,
+		Description: meta.Description,
+	}
+	if meta.Image != "" {
+		preview.Thumbnail, _ = p.thumbnail(ctx, meta.Image)
+	}
+	return preview, nil
+}
+
+func (p *linkPreviewer) thumbnail(ctx context.Context, rawURL string) ([]byte, error) {
+	resp, err := openURL(ctx, p.client, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := readLimited(resp, previewMaxImageBytes)
+	if err != nil {
+		return nil, err
+	}
+	return previewThumbnail(data), nil
+}
+
+// previewThumbnail scales an image down to a JPEG preview thumbnail. It
+// returns nil for anything that does not decode as an image.
+func previewThumbnail(data []byte) []byte {
+	img, _, err := media.DecodeImage(data)
+	if err != nil {
+		return nil
+	}
+	thumb, err := media.JPEGThumbnail(img, previewThumbnailSize)
+	if err != nil {
+		return nil
+	}
+	return thumb
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultLiveLocationDuration = 15 * time.Minute
+	maxLiveLocationDuration     = 8 * time.Hour
+	defaultLiveLocationInterval = 30 * time.Second
+	minLiveLocationInterval     = 10 * time.Second
+)
+
+type StartLiveLocationInput struct {
+	Session        string
+	To             string
+	Latitude       float64
+	Longitude      float64
+	AccuracyMeters uint32
+	Caption        string
+	Duration       time.Duration
+	Interval       time.Duration
+}
+
+type UpdateLiveLocationInput struct {
+	Session        string
+	ID             string
+	Latitude       float64
+	Longitude      float64
+	AccuracyMeters uint32
+}
+
+type LiveLocationOutput struct {
+	Status    string
+	ID        string
+	ExpiresAt time.Time
+}
+
+type LiveLocationUsecase struct {
+	wa *wa.Manager
+}
+
+func NewLiveLocationUsecase(waManager *wa.Manager) *LiveLocationUsecase {
+	return &LiveLocationUsecase{wa: waManager}
+}
+
+// Start sends the live location message and keeps updating it with the
+// latest position every interval until Stop is called or the share expires,
+// when a last update is sent. The returned ID is the message ID every update
+// is sent under.
+func (u *LiveLocationUsecase) Start(ctx context.Context, in StartLiveLocationInput) (*LiveLocationOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+	if err := validateCoordinates(in.Latitude, in.Longitude); err != nil {
+		return nil, err
+	}
+
+	duration := in.Duration
+	if duration <= 0 {
+		duration = defaultLiveLocationDuration
+	}
+	if duration > maxLiveLocationDuration {
+		return nil, fmt.Errorf("duration must be at most %s", maxLiveLocationDuration)
+	}
+	interval := in.Interval
+	if interval <= 0 {
+		interval = defaultLiveLocationInterval
+	}
+	if interval < minLiveLocationInterval {
+		return nil, fmt.Errorf("interval must be at least %s", minLiveLocationInterval)
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	loc := wa.LiveLocation{
+		Session:        in.Session,
+		To:             recipient,
+		Latitude:       in.Latitude,
+		Longitude:      in.Longitude,
+		AccuracyMeters: in.AccuracyMeters,
+		Caption:        strings.TrimSpace(in.Caption),
+		Interval:       interval,
+		StartedAt:      now,
+		ExpiresAt:      now.Add(duration),
+	}
+
+	resp, err := sendLiveLocation(ctx, u.wa, client, loc)
+	if err != nil {
+		return nil, err
+	}
+	loc.ID = resp.ID
+
+	if err := u.wa.StartLiveLocation(loc, u.resend); err != nil {
+		return nil, err
+	}
+
+	return &LiveLocationOutput{Status: "sharing", ID: loc.ID, ExpiresAt: loc.ExpiresAt}, nil
+}
+
+// Update changes the position sent from the next interval on. It returns nil
+// when no share with that ID is running.
+func (u *LiveLocationUsecase) Update(ctx context.Context, in UpdateLiveLocationInput) (*LiveLocationOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := validateCoordinates(in.Latitude, in.Longitude); err != nil {
+		return nil, err
+	}
+
+	loc, ok := u.wa.UpdateLiveLocation(in.Session, in.ID, in.Latitude, in.Longitude, in.AccuracyMeters)
+	if !ok {
+		return nil, nil
+	}
+
+	return &LiveLocationOutput{Status: "sharing", ID: loc.ID, ExpiresAt: loc.ExpiresAt}, nil
+}
+
+func (u *LiveLocationUsecase) Stop(ctx context.Context, session, id string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return u.wa.StopLiveLocation(session, id), nil
+}
+
+func (u *LiveLocationUsecase) resend(ctx context.Context, loc wa.LiveLocation) error {
+	client, err := connectedClient(ctx, u.wa, loc.Session)
+	if err != nil {
+		return err
+	}
+	_, err = sendLiveLocation(ctx, u.wa, client, loc)
+	return err
+}
+
+// sendLiveLocation sends loc as a new live location message when it has no
+// ID yet, and as an update of that message otherwise; WhatsApp tells updates
+// apart by their sequence number.
+func sendLiveLocation(ctx context.Context, waManager *wa.Manager, client *whatsmeow.Client, loc wa.LiveLocation) (whatsmeow.SendResponse, error) {
+	live := &waE2E.LiveLocationMessage{
+		DegreesLatitude:  proto.Float64(loc.Latitude),
+		DegreesLongitude: proto.Float64(loc.Longitude),
+		SequenceNumber:   proto.Int64(loc.Sequence),
+		TimeOffset:       proto.Uint32(uint32(time.Since(loc.StartedAt).Seconds())),
+	}
+	if loc.AccuracyMeters > 0 {
+		live.AccuracyInMeters = proto.Uint32(loc.AccuracyMeters)
+	}
+	if loc.Caption != "" {
+		live.Caption = proto.String(loc.Caption)
+	}
+
+	return sendMessage(ctx, waManager, loc.Session, client, loc.To, &waE2E.Message{LiveLocationMessage: live}, SendOptions{messageID: loc.ID})
+}
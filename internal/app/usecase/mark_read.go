@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+type MarkReadInput struct {
+	Session string
+	Chat    string
+	// MessageIDs are the messages to mark read. When empty, every message
+	// received in the chat since it was last marked read is.
+	MessageIDs []string
+	// Participant is the sender of the messages, needed only in groups for
+	// messages the gateway has not seen.
+	Participant string
+}
+
+type MarkReadOutput struct {
+	Status string
+	Chat   string
+	// MessageIDs are the messages receipts were sent for. The session's own
+	// messages are skipped.
+	MessageIDs []string
+}
+
+type MarkReadUsecase struct {
+	wa *wa.Manager
+}
+
+func NewMarkReadUsecase(waManager *wa.Manager) *MarkReadUsecase {
+	return &MarkReadUsecase{wa: waManager}
+}
+
+// Execute sends read receipts, so senders see blue ticks.
+func (u *MarkReadUsecase) Execute(ctx context.Context, in MarkReadInput) (*MarkReadOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	chat, err := parseRecipient(in.Chat)
+	if err != nil {
+		return nil, err
+	}
+	if chat.Server == types.NewsletterServer || chat == types.StatusBroadcastJID {
+		return nil, fmt.Errorf("read receipts can only be sent in chats")
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []wa.ReadTarget
+	if len(in.MessageIDs) == 0 {
+		targets, err = u.wa.UnreadMessages(ctx, in.Session, chat)
+		if err != nil {
+			return nil, fmt.Errorf("load unread messages: %w", err)
+		}
+	} else {
+		for _, raw := range in.MessageIDs {
+			id := strings.TrimSpace(raw)
+			if id == "" {
+				return nil, fmt.Errorf("message ids must not be empty")
+			}
+			stored, sender, err := resolveMessageTarget(ctx, u.wa, in.Session, client, chat, id, in.Participant)
+			if err != nil {
+				return nil, fmt.Errorf("message %s: %w", id, err)
+			}
+			if stored != nil && stored.FromMe {
+				continue
+			}
+			target := wa.ReadTarget{ID: id, Sender: sender}
+			if stored != nil {
+				target.Timestamp = stored.Timestamp
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	out := &MarkReadOutput{Status: "read", Chat: chat.String(), MessageIDs: make([]string, 0, len(targets))}
+	if len(targets) == 0 {
+		return out, nil
+	}
+
+	if err := wa.SendReadReceipts(ctx, client, chat, targets); err != nil {
+		return nil, err
+	}
+	for _, t := range targets {
+		out.MessageIDs = append(out.MessageIDs, t.ID)
+	}
+
+	if len(in.MessageIDs) == 0 {
+		last := targets[len(targets)-1]
+		if err := u.wa.SaveChatRead(ctx, in.Session, chat, last.Timestamp); err != nil {
+			log.Printf("save read mark of %s: %v", chat, err)
+		}
+		// Clears the unread badge on the session's other devices as well.
+		patch := appstate.BuildMarkChatAsRead(chat, true, last.Timestamp, &waCommon.MessageKey{
+			RemoteJID:   proto.String(chat.String()),
+			FromMe:      proto.Bool(false),
+			ID:          proto.String(last.ID),
+			Participant: participantKey(chat, last.Sender),
+		})
+		if err := client.SendAppState(ctx, patch); err != nil {
+			log.Printf("mark chat %s read on other devices: %v", chat, err)
+		}
+	}
+
+	return out, nil
+}
+
+func participantKey(chat, sender types.JID) *string {
+	if chat.Server != types.GroupServer {
+		return nil
+	}
+	return proto.String(sender.ToNonAD().String())
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/media"
+)
+
+// MaxMediaBytes caps the size of any media the gateway will upload.
+const MaxMediaBytes = 64 << 20
+
+const mediaFetchTimeout = 30 * time.Second
+
+var mediaHTTPClient = newPublicHTTPClient(mediaFetchTimeout)
+
+// MediaSource is media supplied by the caller, either inline or as a remote
+// URL the gateway downloads itself.
+type MediaSource struct {
+	Data     []byte
+	URL      string
+	Filename string
+	Mimetype string
+}
+
+type loadedMedia struct {
+	Data     []byte
+	Filename string
+	Mimetype string
+}
+
+func loadMedia(ctx context.Context, src MediaSource) (*loadedMedia, error) {
+	out := &loadedMedia{
+		Data:     src.Data,
+		Filename: strings.TrimSpace(src.Filename),
+		Mimetype: strings.TrimSpace(src.Mimetype),
+	}
+
+	if len(out.Data) == 0 {
+		rawURL := strings.TrimSpace(src.URL)
+		if rawURL == "" {
+			return nil, fmt.Errorf("media file or url is required")
+		}
+
+		data, contentType, err := fetchMedia(ctx, rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("fetch media: %w", err)
+		}
+		out.Data = data
+		if out.Mimetype == "" {
+			out.Mimetype = contentType
+		}
+		if out.Filename == "" {
+			out.Filename = filenameFromURL(rawURL)
+		}
+	}
+
+	if len(out.Data) > MaxMediaBytes {
+		return nil, fmt.Errorf("media exceeds %d bytes", MaxMediaBytes)
+	}
+
+	out.Mimetype = media.DetectMimetype(out.Data, out.Filename, out.Mimetype)
+	return out, nil
+}
+
+func fetchMedia(ctx context.Context, rawURL string) ([]byte, string, error) {
+	resp, err := openURL(ctx, mediaHTTPClient, rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := readLimited(resp, MaxMediaBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// openURL GETs an http(s) URL and fails on any non-2xx status. client should
+// come from newPublicHTTPClient.
+func openURL(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkURLScheme(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// readLimited reads the whole body, failing once it grows past limit.
+func readLimited(resp *http.Response, limit int64) ([]byte, error) {
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("media exceeds %d bytes", limit)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("media exceeds %d bytes", limit)
+	}
+	return data, nil
+}
+
+func filenameFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
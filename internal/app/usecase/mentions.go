@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/phone"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+var mentionTokenRe = regexp.MustCompile(`(?:^|[^\w@])@\+?(\d{9,15})\b`)
+
+// resolveMentions collects the JIDs a group message should mention: every
+// @<phone> token in text, the explicit phones, and with all set every
+// participant of the group except the session itself.
+func resolveMentions(ctx context.Context, client *whatsmeow.Client, group types.JID, text string, explicit []string, all bool) ([]string, error) {
+	seen := make(map[string]struct{})
+	out := make([]string, 0)
+	add := func(jid types.JID) {
+		key := jid.ToNonAD().String()
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		out = append(out, key)
+	}
+
+	for _, match := range mentionTokenRe.FindAllStringSubmatch(text, -1) {
+		p, err := phone.Normalize(match[1])
+		if err != nil {
+			continue
+		}
+		add(types.NewJID(p, types.DefaultUserServer))
+	}
+
+	for _, raw := range explicit {
+		p, err := phone.Normalize(raw)
+		if err != nil {
+			return nil, fmt.Errorf("mention %q: %w", raw, err)
+		}
+		add(types.NewJID(p, types.DefaultUserServer))
+	}
+
+	if all {
+		info, err := client.GetGroupInfo(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("get group info: %w", err)
+		}
+		own := client.Store.ID.ToNonAD()
+		ownLID := client.Store.GetLID().ToNonAD()
+		for _, participant := range info.Participants {
+			jid := participant.JID.ToNonAD()
+			if jid == own || jid == ownLID {
+				continue
+			}
+			add(jid)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// contextInfoOf returns the ContextInfo of msg's content, creating it when
+// missing. A plain Conversation has nowhere to hold one, so it is turned into
+// an ExtendedTextMessage first. View-once content is looked up inside its
+// container. It returns nil for content that carries no ContextInfo.
+func contextInfoOf(msg *waE2E.Message) *waE2E.ContextInfo {
+	if inner := viewOnceContent(msg); inner != nil {
+		return contextInfoOf(inner)
+	}
+	if msg.Conversation != nil {
+		msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{Text: msg.Conversation}
+		msg.Conversation = nil
+	}
+
+	switch {
+	case msg.ExtendedTextMessage != nil:
+		if msg.ExtendedTextMessage.ContextInfo == nil {
+			msg.ExtendedTextMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.ExtendedTextMessage.ContextInfo
+	case msg.ImageMessage != nil:
+		if msg.ImageMessage.ContextInfo == nil {
+			msg.ImageMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.ImageMessage.ContextInfo
+	case msg.VideoMessage != nil:
+		if msg.VideoMessage.ContextInfo == nil {
+			msg.VideoMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.VideoMessage.ContextInfo
+	case msg.AudioMessage != nil:
+		if msg.AudioMessage.ContextInfo == nil {
+			msg.AudioMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.AudioMessage.ContextInfo
+	case msg.DocumentMessage != nil:
+		if msg.DocumentMessage.ContextInfo == nil {
+			msg.DocumentMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.DocumentMessage.ContextInfo
+	case msg.StickerMessage != nil:
+		if msg.StickerMessage.ContextInfo == nil {
+			msg.StickerMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.StickerMessage.ContextInfo
+	case msg.LocationMessage != nil:
+		if msg.LocationMessage.ContextInfo == nil {
+			msg.LocationMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.LocationMessage.ContextInfo
+	case msg.LiveLocationMessage != nil:
+		if msg.LiveLocationMessage.ContextInfo == nil {
+			msg.LiveLocationMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.LiveLocationMessage.ContextInfo
+	case msg.ContactMessage != nil:
+		if msg.ContactMessage.ContextInfo == nil {
+			msg.ContactMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.ContactMessage.ContextInfo
+	case msg.ContactsArrayMessage != nil:
+		if msg.ContactsArrayMessage.ContextInfo == nil {
+			msg.ContactsArrayMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.ContactsArrayMessage.ContextInfo
+	case msg.PollCreationMessage != nil:
+		if msg.PollCreationMessage.ContextInfo == nil {
+			msg.PollCreationMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		return msg.PollCreationMessage.ContextInfo
+	}
+
+	return nil
+}
+
+// quotableContent strips what a quote should not carry along, such as the
+// quoted message's own reply context.
+func quotableContent(msg *waE2E.Message) *waE2E.Message {
+	quoted := proto.Clone(msg).(*waE2E.Message)
+	quoted.MessageContextInfo = nil
+	if ci := contextInfoOf(quoted); ci != nil {
+		ci.Reset()
+	}
+	return quoted
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	defaultNewsletterMessages = 20
+	maxNewsletterMessages     = 100
+)
+
+var ErrNotChannelAdmin = errors.New("only channel admins can post to a channel")
+
+type NewsletterInfo struct {
+	JID         string
+	Name        string
+	Description string
+	InviteLink  string
+	Subscribers int
+	Verified    bool
+	State       string
+	// Role and Muted describe the session's relation to the channel. They are
+	// empty for channels looked up by invite link.
+	Role  string
+	Muted bool
+}
+
+type NewsletterMessagesInput struct {
+	Session string
+	JID     string
+	Count   int
+	// Before pages back from a server ID returned by an earlier call.
+	Before int
+}
+
+type NewsletterMessageItem struct {
+	ServerID  int
+	MessageID string
+	Type      string
+	Text      string
+	Timestamp time.Time
+	Views     int
+	Reactions map[string]int
+}
+
+type NewsletterUsecase struct {
+	wa *wa.Manager
+}
+
+func NewNewsletterUsecase(waManager *wa.Manager) *NewsletterUsecase {
+	return &NewsletterUsecase{wa: waManager}
+}
+
+// List returns the channels the session follows or administers.
+func (u *NewsletterUsecase) List(ctx context.Context, session string) ([]NewsletterInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, session)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.GetSubscribedNewsletters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]NewsletterInfo, 0, len(list))
+	for _, meta := range list {
+		out = append(out, newsletterInfo(meta))
+	}
+	return out, nil
+}
+
+// Lookup resolves a channel invite link, or just its code.
+func (u *NewsletterUsecase) Lookup(ctx context.Context, session, invite string) (*NewsletterInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	invite = strings.TrimSpace(invite)
+	if invite == "" {
+		return nil, fmt.Errorf("invite is required")
+	}
+
+	client, err := connectedClient(ctx, u.wa, session)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := client.GetNewsletterInfoWithInvite(ctx, invite)
+	if err != nil {
+		return nil, err
+	}
+
+	info := newsletterInfo(meta)
+	return &info, nil
+}
+
+func (u *NewsletterUsecase) Follow(ctx context.Context, session, jidRaw string) error {
+	return u.withNewsletter(ctx, session, jidRaw, (*whatsmeow.Client).FollowNewsletter)
+}
+
+func (u *NewsletterUsecase) Unfollow(ctx context.Context, session, jidRaw string) error {
+	return u.withNewsletter(ctx, session, jidRaw, (*whatsmeow.Client).UnfollowNewsletter)
+}
+
+func (u *NewsletterUsecase) withNewsletter(ctx context.Context, session, jidRaw string, do func(*whatsmeow.Client, context.Context, types.JID) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	jid, err := parseNewsletter(jidRaw)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectedClient(ctx, u.wa, session)
+	if err != nil {
+		return err
+	}
+
+	return do(client, ctx, jid)
+}
+
+// Messages returns a channel's most recent messages, newest first.
+func (u *NewsletterUsecase) Messages(ctx context.Context, in NewsletterMessagesInput) ([]NewsletterMessageItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	jid, err := parseNewsletter(in.JID)
+	if err != nil {
+		return nil, err
+	}
+	count := in.Count
+	if count <= 0 {
+		count = defaultNewsletterMessages
+	}
+	if count > maxNewsletterMessages {
+		count = maxNewsletterMessages
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := client.GetNewsletterMessages(ctx, jid, &whatsmeow.GetNewsletterMessagesParams{
+		Count:  count,
+		Before: types.MessageServerID(in.Before),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]NewsletterMessageItem, 0, len(messages))
+	for _, m := range messages {
+		reactions := m.ReactionCounts
+		if reactions == nil {
+			reactions = map[string]int{}
+		}
+		out = append(out, NewsletterMessageItem{
+			ServerID:  int(m.MessageServerID),
+			MessageID: m.MessageID,
+			Type:      m.Type,
+			Text:      wa.MessageText(m.Message),
+			Timestamp: m.Timestamp,
+			Views:     m.ViewsCount,
+			Reactions: reactions,
+		})
+	}
+	return out, nil
+}
+
+// ensureChannelAdmin fails unless the session can post to the channel. The
+// server would refuse the post anyway, but without saying why.
+func ensureChannelAdmin(ctx context.Context, client *whatsmeow.Client, channel types.JID) error {
+	meta, err := client.GetNewsletterInfo(ctx, channel)
+	if err != nil {
+		return fmt.Errorf("get channel info: %w", err)
+	}
+	if meta.ViewerMeta == nil {
+		return ErrNotChannelAdmin
+	}
+	switch meta.ViewerMeta.Role {
+	case types.NewsletterRoleAdmin, types.NewsletterRoleOwner:
+		return nil
+	}
+	return ErrNotChannelAdmin
+}
+
+func parseNewsletter(raw string) (types.JID, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return types.JID{}, fmt.Errorf("channel jid is required")
+	}
+	if !strings.Contains(raw, "@") {
+		raw += "@" + types.NewsletterServer
+	}
+
+	jid, err := types.ParseJID(raw)
+	if err != nil {
+		return types.JID{}, err
+	}
+	if jid.Server != types.NewsletterServer {
+		return types.JID{}, fmt.Errorf("%s is not a channel", jid)
+	}
+	return jid, nil
+}
+
+func newsletterInfo(meta *types.NewsletterMetadata) NewsletterInfo {
+	info := NewsletterInfo{
+		JID:         meta.ID.String(),
+		Name:        meta.ThreadMeta.Name.Text,
+		Description: meta.ThreadMeta.Description.Text,
+		Subscribers: meta.ThreadMeta.SubscriberCount,
+		Verified:    meta.ThreadMeta.VerificationState == types.NewsletterVerificationStateVerified,
+		State:       string(meta.State.Type),
+	}
+	if code := meta.ThreadMeta.InviteCode; code != "" {
+		info.InviteLink = whatsmeow.NewsletterLinkPrefix + code
+	}
+	if meta.ViewerMeta != nil {
+		info.Role = string(meta.ViewerMeta.Role)
+		info.Muted = meta.ViewerMeta.Mute == types.NewsletterMuteOn
+	}
+	return info
+}
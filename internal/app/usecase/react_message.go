@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+)
+
+type ReactMessageInput struct {
+	Session   string
+	Chat      string
+	MessageID string
+	// Participant is the sender of the message, needed only in groups when
+	// the gateway has not seen the message.
+	Participant string
+	// Emoji is the reaction to send; empty removes the session's reaction.
+	Emoji string
+}
+
+type ReactMessageOutput struct {
+	Status    string
+	MessageID string
+}
+
+type ReactionItem struct {
+	Sender    string
+	Emoji     string
+	Timestamp time.Time
+}
+
+type ReactMessageUsecase struct {
+	wa *wa.Manager
+}
+
+func NewReactMessageUsecase(waManager *wa.Manager) *ReactMessageUsecase {
+	return &ReactMessageUsecase{wa: waManager}
+}
+
+func (u *ReactMessageUsecase) Execute(ctx context.Context, in ReactMessageInput) (*ReactMessageOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.Chat) == "" {
+		return nil, fmt.Errorf("chat is required")
+	}
+	if strings.TrimSpace(in.MessageID) == "" {
+		return nil, fmt.Errorf("message id is required")
+	}
+
+	chat, err := parseRecipient(in.Chat)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	_, sender, err := resolveMessageTarget(ctx, u.wa, in.Session, client, chat, in.MessageID, in.Participant)
+	if err != nil {
+		return nil, err
+	}
+
+	emoji := strings.TrimSpace(in.Emoji)
+	resp, err := client.SendMessage(ctx, chat, client.BuildReaction(chat, sender, in.MessageID, emoji))
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.wa.SaveReaction(ctx, in.Session, wa.Reaction{
+		Chat:      chat,
+		MessageID: in.MessageID,
+		Sender:    client.Store.ID.ToNonAD(),
+		Emoji:     emoji,
+		Timestamp: resp.Timestamp,
+	})
+	if err != nil {
+		log.Printf("store reaction to %s: %v", in.MessageID, err)
+	}
+
+	status := "reacted"
+	if emoji == "" {
+		status = "removed"
+	}
+	return &ReactMessageOutput{Status: status, MessageID: resp.ID}, nil
+}
+
+// List returns the current reactions to a message, as seen by the gateway.
+func (u *ReactMessageUsecase) List(ctx context.Context, session, chatRaw, messageID string) ([]ReactionItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := requireSession(u.wa, session); err != nil {
+		return nil, err
+	}
+
+	chat, err := parseRecipient(chatRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	reactions, err := u.wa.ListReactions(ctx, session, chat, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ReactionItem, 0, len(reactions))
+	for _, r := range reactions {
+		out = append(out, ReactionItem{
+			Sender:    r.Sender.String(),
+			Emoji:     r.Emoji,
+			Timestamp: r.Timestamp,
+		})
+	}
+	return out, nil
+}
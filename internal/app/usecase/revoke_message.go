@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+var ErrNotGroupAdmin = errors.New("only group admins can revoke messages sent by others")
+
+type RevokeMessageInput struct {
+	Session   string
+	Chat      string
+	MessageID string
+	// Participant is the sender of the message, needed only in groups when
+	// the gateway has not seen the message.
+	Participant string
+}
+
+type RevokeMessageOutput struct {
+	Status    string
+	Accepted  bool
+	MessageID string
+	Detail    string
+}
+
+type RevokeMessageUsecase struct {
+	wa *wa.Manager
+}
+
+func NewRevokeMessageUsecase(waManager *wa.Manager) *RevokeMessageUsecase {
+	return &RevokeMessageUsecase{wa: waManager}
+}
+
+// Execute revokes a message for everyone. Messages from other participants
+// can only be revoked in groups where the session is an admin. A revoke that
+// reaches WhatsApp but is rejected is reported with Accepted false rather
+// than as an error; failing to reach WhatsApp at all is an error.
+func (u *RevokeMessageUsecase) Execute(ctx context.Context, in RevokeMessageInput) (*RevokeMessageOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.MessageID) == "" {
+		return nil, fmt.Errorf("message id is required")
+	}
+
+	chat, err := parseRecipient(in.Chat)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	participant := in.Participant
+	if participant == "" && chat.Server != types.GroupServer {
+		// In a direct chat only our own messages can be revoked.
+		participant = client.Store.ID.ToNonAD().String()
+	}
+	_, sender, err := resolveMessageTarget(ctx, u.wa, in.Session, client, chat, in.MessageID, participant)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isOwnJID(client, sender) {
+		if chat.Server != types.GroupServer {
+			return nil, fmt.Errorf("messages from others can only be revoked in groups")
+		}
+		admin, err := isGroupAdmin(ctx, client, chat)
+		if err != nil {
+			return nil, err
+		}
+		if !admin {
+			return nil, ErrNotGroupAdmin
+		}
+	}
+
+	resp, err := client.SendMessage(ctx, chat, client.BuildRevoke(chat, sender, in.MessageID))
+	if errors.Is(err, whatsmeow.ErrServerReturnedError) {
+		return &RevokeMessageOutput{Status: "failed", Accepted: false, Detail: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.wa.DeleteMessage(ctx, in.Session, chat, in.MessageID); err != nil {
+		log.Printf("forget revoked message %s: %v", in.MessageID, err)
+	}
+	return &RevokeMessageOutput{Status: "revoked", Accepted: true, MessageID: resp.ID}, nil
+}
+
+func isOwnJID(client *whatsmeow.Client, jid types.JID) bool {
+	if jid.IsEmpty() {
+		return false
+	}
+	jid = jid.ToNonAD()
+	return jid == client.Store.ID.ToNonAD() || jid == client.Store.GetLID().ToNonAD()
+}
+
+func isGroupAdmin(ctx context.Context, client *whatsmeow.Client, group types.JID) (bool, error) {
+	info, err := client.GetGroupInfo(ctx, group)
+	if err != nil {
+		return false, fmt.Errorf("get group info: %w", err)
+	}
+	for _, p := range info.Participants {
+		if isOwnJID(client, p.JID) || isOwnJID(client, p.PhoneNumber) || isOwnJID(client, p.LID) {
+			return p.IsAdmin || p.IsSuperAdmin, nil
+		}
+	}
+	return false, nil
+}
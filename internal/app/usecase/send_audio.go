@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/media"
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type SendAudioInput struct {
+	Session string
+	To      string
+	PTT     bool
+	Media   MediaSource
+	// ViewOnce lets the recipient open the audio only once.
+	ViewOnce bool
+	SendOptions
+}
+
+type SendAudioUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSendAudioUsecase(waManager *wa.Manager) *SendAudioUsecase {
+	return &SendAudioUsecase{wa: waManager}
+}
+
+func (u *SendAudioUsecase) Execute(ctx context.Context, in SendAudioInput) (*SendMediaOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := loadMedia(ctx, in.Media)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(file.Mimetype, "audio/") {
+		return nil, fmt.Errorf("unsupported audio type %q", file.Mimetype)
+	}
+
+	audio := &waE2E.AudioMessage{
+		Mimetype: proto.String(file.Mimetype),
+		PTT:      proto.Bool(in.PTT),
+	}
+
+	if file.Mimetype == "audio/ogg" {
+		info, err := media.ParseOggOpus(file.Data)
+		if err != nil {
+			return nil, fmt.Errorf("parse ogg: %w", err)
+		}
+		audio.Mimetype = proto.String("audio/ogg; codecs=opus")
+		audio.Seconds = proto.Uint32(uint32(info.Duration.Round(time.Second) / time.Second))
+		if in.PTT {
+			audio.Waveform = info.Waveform
+		}
+	} else if in.PTT {
+		return nil, fmt.Errorf("voice notes must be ogg/opus, got %q", file.Mimetype)
+	}
+
+	return sendUploaded(ctx, u.wa, in.Session, recipient, in.SendOptions, file.Data, whatsmeow.MediaAudio, func(up whatsmeow.UploadResponse) *waE2E.Message {
+		audio.URL = proto.String(up.URL)
+		audio.DirectPath = proto.String(up.DirectPath)
+		audio.MediaKey = up.MediaKey
+		audio.FileEncSHA256 = up.FileEncSHA256
+		audio.FileSHA256 = up.FileSHA256
+		audio.FileLength = proto.Uint64(up.FileLength)
+		msg := &waE2E.Message{AudioMessage: audio}
+		if in.ViewOnce {
+			return wrapViewOnce(msg)
+		}
+		return msg
+	})
+}
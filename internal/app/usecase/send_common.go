@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// requireSession returns ErrSessionNotFound unless the gateway knows session,
+// for requests that only read what it has stored.
+func requireSession(waManager *wa.Manager, session string) error {
+	exists, err := waManager.SessionExists(session)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// connectedClient returns the session's client once it is connected and
+// logged in, which every send path needs before it can talk to WhatsApp.
+func connectedClient(ctx context.Context, waManager *wa.Manager, session string) (*whatsmeow.Client, error) {
+	if strings.TrimSpace(session) == "" {
+		return nil, fmt.Errorf("session is required")
+	}
+
+	client, err := waManager.CreateOrGetClientBySession(session)
+	if err != nil {
+		return nil, fmt.Errorf("create client: %w", err)
+	}
+
+	if err := waManager.EnsureConnected(ctx, session, client); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	if client.Store.ID == nil {
+		return nil, fmt.Errorf("session not logged in")
+	}
+
+	return client, nil
+}
+
+// ReplyTo quotes an earlier message of the chat being sent to.
+type ReplyTo struct {
+	MessageID string
+	// Participant is the sender of the quoted message, as a phone number or
+	// JID. It is only needed when the gateway has no copy of that message.
+	Participant string
+	// Text is shown as the quote when the gateway has no copy of the message.
+	Text string
+}
+
+// SendOptions are accepted by every send usecase.
+type SendOptions struct {
+	ReplyTo *ReplyTo
+	// SimulateTyping shows the recipient a typing (or recording, for voice
+	// notes) indicator for a while before the message goes out.
+	SimulateTyping bool
+	// Async returns as soon as the message is ready to go and sends it in the
+	// background. Failures are only logged.
+	Async bool
+
+	// mediaHandle is the upload handle channel posts carry alongside media.
+	mediaHandle string
+	// messageID sends the message under an existing ID instead of a new one,
+	// as live location updates do.
+	messageID string
+	// statusAudience is who a status post goes to; empty means the session's
+	// contacts.
+	statusAudience []types.JID
+	// prepare finishes msg right before it goes out, for work like fetching
+	// a link preview that an async send should not wait for.
+	prepare func(ctx context.Context, msg *waE2E.Message)
+}
+
+// asyncSendTimeout bounds a background send, typing delay included.
+const asyncSendTimeout = 2 * time.Minute
+
+// sentStatus is the status send usecases report for a message sent with opts.
+func sentStatus(opts SendOptions) string {
+	if opts.Async {
+		return "queued"
+	}
+	return "sent"
+}
+
+// sendMessage applies opts and the chat's disappearing timer to msg, sends it
+// and keeps a copy in the session store so later messages can quote it.
+// Everything that can be checked up front is, so an async send only fails
+// in the background for reasons WhatsApp gives.
+func sendMessage(ctx context.Context, waManager *wa.Manager, session string, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, opts SendOptions) (whatsmeow.SendResponse, error) {
+	if recipient.Server == types.NewsletterServer {
+		if opts.ReplyTo != nil {
+			return whatsmeow.SendResponse{}, fmt.Errorf("replies are not supported in channels")
+		}
+		if err := ensureChannelAdmin(ctx, client, recipient); err != nil {
+			return whatsmeow.SendResponse{}, err
+		}
+	}
+	if opts.ReplyTo != nil {
+		if err := applyReplyTo(ctx, waManager, session, client, recipient, msg, opts.ReplyTo); err != nil {
+			return whatsmeow.SendResponse{}, err
+		}
+	}
+	if err := applyExpiration(ctx, waManager, session, client, recipient, msg); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	extra := whatsmeow.SendRequestExtra{
+		ID:          opts.messageID,
+		MediaHandle: opts.mediaHandle,
+	}
+	if extra.ID == "" {
+		extra.ID = client.GenerateMessageID()
+	}
+	if !opts.Async {
+		return deliverMessage(ctx, waManager, session, client, recipient, msg, opts, extra)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), asyncSendTimeout)
+		defer cancel()
+		if _, err := deliverMessage(ctx, waManager, session, client, recipient, msg, opts, extra); err != nil {
+			log.Printf("async send %s to %s: %v", extra.ID, recipient, err)
+		}
+	}()
+	return whatsmeow.SendResponse{ID: extra.ID, Timestamp: time.Now()}, nil
+}
+
+func deliverMessage(ctx context.Context, waManager *wa.Manager, session string, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, opts SendOptions, extra whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	if opts.prepare != nil {
+		opts.prepare(ctx, msg)
+	}
+	if opts.SimulateTyping && showsTyping(recipient) {
+		if err := simulateTyping(ctx, client, recipient, msg); err != nil {
+			return whatsmeow.SendResponse{}, err
+		}
+		defer stopTyping(client, recipient)
+	}
+
+	var (
+		resp whatsmeow.SendResponse
+		err  error
+	)
+	if recipient == types.StatusBroadcastJID {
+		resp, err = wa.SendStatus(ctx, client, msg, opts.statusAudience, extra)
+	} else {
+		resp, err = client.SendMessage(ctx, recipient, msg, extra)
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	err = waManager.SaveMessage(ctx, session, wa.StoredMessage{
+		ID:        resp.ID,
+		Chat:      recipient,
+		Sender:    client.Store.ID.ToNonAD(),
+		FromMe:    true,
+		Timestamp: resp.Timestamp,
+		Message:   msg,
+	})
+	if err != nil {
+		log.Printf("store sent message %s: %v", resp.ID, err)
+	}
+
+	return resp, nil
+}
+
+func applyReplyTo(ctx context.Context, waManager *wa.Manager, session string, client *whatsmeow.Client, chat types.JID, msg *waE2E.Message, reply *ReplyTo) error {
+	id := strings.TrimSpace(reply.MessageID)
+	if id == "" {
+		return fmt.Errorf("reply_to.message_id is required")
+	}
+
+	ci := contextInfoOf(msg)
+	if ci == nil {
+		return fmt.Errorf("this message type cannot be a reply")
+	}
+
+	stored, participant, err := resolveMessageTarget(ctx, waManager, session, client, chat, id, reply.Participant)
+	if err != nil {
+		return fmt.Errorf("reply_to: %w", err)
+	}
+
+	ci.StanzaID = proto.String(id)
+	ci.Participant = proto.String(participant.String())
+	if stored != nil {
+		ci.QuotedMessage = quotableContent(stored.Message)
+	} else {
+		ci.QuotedMessage = &waE2E.Message{Conversation: proto.String(reply.Text)}
+	}
+	return nil
+}
+
+// resolveMessageTarget finds who sent message id in chat, which WhatsApp
+// needs to address replies, reactions and revokes. The gateway's own copy
+// wins; otherwise participant is used, and in direct chats the other side is
+// assumed. stored is nil when the gateway has no copy.
+func resolveMessageTarget(ctx context.Context, waManager *wa.Manager, session string, client *whatsmeow.Client, chat types.JID, id, participant string) (stored *wa.StoredMessage, sender types.JID, err error) {
+	stored, found, err := waManager.GetMessage(ctx, session, chat, id)
+	if err != nil {
+		return nil, types.JID{}, fmt.Errorf("load message: %w", err)
+	}
+
+	switch {
+	case found && stored.FromMe:
+		return stored, client.Store.ID.ToNonAD(), nil
+	case found:
+		return stored, stored.Sender, nil
+	case strings.TrimSpace(participant) != "":
+		sender, err = parseRecipient(participant)
+		if err != nil {
+			return nil, types.JID{}, fmt.Errorf("participant: %w", err)
+		}
+		return nil, sender, nil
+	case chat.Server == types.GroupServer:
+		return nil, types.JID{}, fmt.Errorf("participant is required for messages the gateway has not seen")
+	}
+	return nil, chat, nil
+}
+
+// SendMediaOutput is returned by every media send usecase.
+type SendMediaOutput struct {
+	Status    string
+	MessageID string
+}
+
+// sendUploaded is the send path shared by every media usecase: it uploads data
+// from the session's client, lets build wrap the upload into a message and
+// sends that message to recipient.
+func sendUploaded(ctx context.Context, waManager *wa.Manager, session string, recipient types.JID, opts SendOptions, data []byte, mediaType whatsmeow.MediaType, build func(up whatsmeow.UploadResponse) *waE2E.Message) (*SendMediaOutput, error) {
+	client, err := connectedClient(ctx, waManager, session)
+	if err != nil {
+		return nil, err
+	}
+
+	// Channel media is public, so it is uploaded without encryption.
+	upload := client.Upload
+	if recipient.Server == types.NewsletterServer {
+		upload = client.UploadNewsletter
+	}
+	up, err := upload(ctx, data, mediaType)
+	if err != nil {
+		return nil, fmt.Errorf("upload: %w", err)
+	}
+	opts.mediaHandle = up.Handle
+
+	resp, err := sendMessage(ctx, waManager, session, client, recipient, build(up), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SendMediaOutput{Status: sentStatus(opts), MessageID: resp.ID}, nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/vcard"
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type SendContactInput struct {
+	Session  string
+	To       string
+	Contacts []vcard.Contact
+	SendOptions
+}
+
+type SendContactOutput struct {
+	Status    string
+	MessageID string
+}
+
+type SendContactUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSendContactUsecase(waManager *wa.Manager) *SendContactUsecase {
+	return &SendContactUsecase{wa: waManager}
+}
+
+// Execute sends a single ContactMessage, or a ContactsArrayMessage when more
+// than one contact is given.
+func (u *SendContactUsecase) Execute(ctx context.Context, in SendContactInput) (*SendContactOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+	if len(in.Contacts) == 0 {
+		return nil, fmt.Errorf("at least one contact is required")
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*waE2E.ContactMessage, 0, len(in.Contacts))
+	for i, contact := range in.Contacts {
+		card, err := vcard.Build(contact)
+		if err != nil {
+			return nil, fmt.Errorf("contact %d: %w", i, err)
+		}
+		cards = append(cards, &waE2E.ContactMessage{
+			DisplayName: proto.String(strings.TrimSpace(contact.Name)),
+			Vcard:       proto.String(card),
+		})
+	}
+
+	msg := &waE2E.Message{}
+	if len(cards) == 1 {
+		msg.ContactMessage = cards[0]
+	} else {
+		msg.ContactsArrayMessage = &waE2E.ContactsArrayMessage{
+			DisplayName: proto.String(fmt.Sprintf("%d contacts", len(cards))),
+			Contacts:    cards,
+		}
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sendMessage(ctx, u.wa, in.Session, client, recipient, msg, in.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SendContactOutput{Status: sentStatus(in.SendOptions), MessageID: resp.ID}, nil
+}
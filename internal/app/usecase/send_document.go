@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/media"
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type SendDocumentInput struct {
+	Session string
+	To      string
+	Caption string
+	Media   MediaSource
+	SendOptions
+}
+
+type SendDocumentUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSendDocumentUsecase(waManager *wa.Manager) *SendDocumentUsecase {
+	return &SendDocumentUsecase{wa: waManager}
+}
+
+func (u *SendDocumentUsecase) Execute(ctx context.Context, in SendDocumentInput) (*SendMediaOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := loadMedia(ctx, in.Media)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := file.Filename
+	if filename == "" {
+		filename = "document"
+		if exts, _ := mime.ExtensionsByType(file.Mimetype); len(exts) > 0 {
+			filename += exts[0]
+		}
+	}
+
+	doc := &waE2E.DocumentMessage{
+		Mimetype: proto.String(file.Mimetype),
+		FileName: proto.String(filename),
+		Title:    proto.String(filename),
+	}
+	if file.Mimetype == "application/pdf" {
+		if pages := media.PDFPageCount(file.Data); pages > 0 {
+			doc.PageCount = proto.Uint32(uint32(pages))
+		}
+	}
+	if caption := strings.TrimSpace(in.Caption); caption != "" {
+		doc.Caption = proto.String(caption)
+	}
+
+	return sendUploaded(ctx, u.wa, in.Session, recipient, in.SendOptions, file.Data, whatsmeow.MediaDocument, func(up whatsmeow.UploadResponse) *waE2E.Message {
+		doc.URL = proto.String(up.URL)
+		doc.DirectPath = proto.String(up.DirectPath)
+		doc.MediaKey = up.MediaKey
+		doc.FileEncSHA256 = up.FileEncSHA256
+		doc.FileSHA256 = up.FileSHA256
+		doc.FileLength = proto.Uint64(up.FileLength)
+		return &waE2E.Message{DocumentMessage: doc}
+	})
+}
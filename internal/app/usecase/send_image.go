@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/media"
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type SendImageInput struct {
+	Session string
+	To      string
+	Caption string
+	Media   MediaSource
+	// ViewOnce lets the recipient open the image only once.
+	ViewOnce bool
+	SendOptions
+}
+
+type SendImageUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSendImageUsecase(waManager *wa.Manager) *SendImageUsecase {
+	return &SendImageUsecase{wa: waManager}
+}
+
+func (u *SendImageUsecase) Execute(ctx context.Context, in SendImageInput) (*SendMediaOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := loadMedia(ctx, in.Media)
+	if err != nil {
+		return nil, err
+	}
+	if file.Mimetype != "image/jpeg" && file.Mimetype != "image/png" {
+		return nil, fmt.Errorf("unsupported image type %q", file.Mimetype)
+	}
+
+	decoded, info, err := media.DecodeImage(file.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	thumb, err := media.JPEGThumbnail(decoded, media.ThumbnailSize)
+	if err != nil {
+		return nil, fmt.Errorf("thumbnail: %w", err)
+	}
+
+	img := &waE2E.ImageMessage{
+		Mimetype:      proto.String(file.Mimetype),
+		Width:         proto.Uint32(uint32(info.Width)),
+		Height:        proto.Uint32(uint32(info.Height)),
+		JPEGThumbnail: thumb,
+	}
+	if caption := strings.TrimSpace(in.Caption); caption != "" {
+		img.Caption = proto.String(caption)
+	}
+
+	return sendUploaded(ctx, u.wa, in.Session, recipient, in.SendOptions, file.Data, whatsmeow.MediaImage, func(up whatsmeow.UploadResponse) *waE2E.Message {
+		img.URL = proto.String(up.URL)
+		img.DirectPath = proto.String(up.DirectPath)
+		img.MediaKey = up.MediaKey
+		img.FileEncSHA256 = up.FileEncSHA256
+		img.FileSHA256 = up.FileSHA256
+		img.FileLength = proto.Uint64(up.FileLength)
+		msg := &waE2E.Message{ImageMessage: img}
+		if in.ViewOnce {
+			return wrapViewOnce(msg)
+		}
+		return msg
+	})
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type SendLocationInput struct {
+	Session   string
+	To        string
+	Latitude  float64
+	Longitude float64
+	Name      string
+	Address   string
+	URL       string
+	SendOptions
+}
+
+type SendLocationOutput struct {
+	Status    string
+	MessageID string
+}
+
+type SendLocationUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSendLocationUsecase(waManager *wa.Manager) *SendLocationUsecase {
+	return &SendLocationUsecase{wa: waManager}
+}
+
+func (u *SendLocationUsecase) Execute(ctx context.Context, in SendLocationInput) (*SendLocationOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+	if err := validateCoordinates(in.Latitude, in.Longitude); err != nil {
+		return nil, err
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := &waE2E.LocationMessage{
+		DegreesLatitude:  proto.Float64(in.Latitude),
+		DegreesLongitude: proto.Float64(in.Longitude),
+	}
+	if name := strings.TrimSpace(in.Name); name != "" {
+		loc.Name = proto.String(name)
+	}
+	if address := strings.TrimSpace(in.Address); address != "" {
+		loc.Address = proto.String(address)
+	}
+	if url := strings.TrimSpace(in.URL); url != "" {
+		loc.URL = proto.String(url)
+	}
+
+	resp, err := sendMessage(ctx, u.wa, in.Session, client, recipient, &waE2E.Message{LocationMessage: loc}, in.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SendLocationOutput{Status: sentStatus(in.SendOptions), MessageID: resp.ID}, nil
+}
+
+func validateCoordinates(lat, lng float64) error {
+	if lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if lng < -180 || lng > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+)
+
+const (
+	minPollOptions = 2
+	maxPollOptions = 12
+)
+
+type SendPollInput struct {
+	Session  string
+	To       string
+	Question string
+	Options  []string
+	// MultiSelect lets voters pick any number of options instead of one.
+	MultiSelect bool
+	SendOptions
+}
+
+type SendPollOutput struct {
+	Status    string
+	MessageID string
+}
+
+type PollOptionTally struct {
+	Name   string
+	Votes  int
+	Voters []string
+}
+
+type PollTally struct {
+	MessageID   string
+	Question    string
+	MultiSelect bool
+	Options     []PollOptionTally
+	UpdatedAt   time.Time
+}
+
+type SendPollUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSendPollUsecase(waManager *wa.Manager) *SendPollUsecase {
+	return &SendPollUsecase{wa: waManager}
+}
+
+func (u *SendPollUsecase) Execute(ctx context.Context, in SendPollInput) (*SendPollOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+	question := strings.TrimSpace(in.Question)
+	if question == "" {
+		return nil, fmt.Errorf("question is required")
+	}
+	options, err := pollOptions(in.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	selectable := 1
+	if in.MultiSelect {
+		selectable = 0
+	}
+
+	// The stored copy keeps the poll's secret, which incoming votes are
+	// decrypted with.
+	msg := client.BuildPollCreation(question, options, selectable)
+	resp, err := sendMessage(ctx, u.wa, in.Session, client, recipient, msg, in.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SendPollOutput{Status: sentStatus(in.SendOptions), MessageID: resp.ID}, nil
+}
+
+// Tally counts the latest vote of every voter on a poll the gateway has seen.
+func (u *SendPollUsecase) Tally(ctx context.Context, session, chatRaw, pollID string) (*PollTally, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := requireSession(u.wa, session); err != nil {
+		return nil, err
+	}
+
+	chat, err := parseRecipient(chatRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, found, err := u.wa.GetMessage(ctx, session, chat, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if !found || wa.PollCreationOf(poll.Message) == nil {
+		return nil, ErrMessageNotFound
+	}
+	creation := wa.PollCreationOf(poll.Message)
+
+	votes, err := u.wa.ListPollVotes(ctx, session, poll.Chat, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	tally := &PollTally{
+		MessageID:   pollID,
+		Question:    creation.GetName(),
+		MultiSelect: creation.GetSelectableOptionsCount() != 1,
+		Options:     make([]PollOptionTally, 0, len(creation.GetOptions())),
+		UpdatedAt:   poll.Timestamp,
+	}
+	index := make(map[string]int, len(creation.GetOptions()))
+	for _, option := range creation.GetOptions() {
+		index[option.GetOptionName()] = len(tally.Options)
+		tally.Options = append(tally.Options, PollOptionTally{Name: option.GetOptionName(), Voters: []string{}})
+	}
+	for _, vote := range votes {
+		for _, name := range vote.Selected {
+			i, ok := index[name]
+			if !ok {
+				continue
+			}
+			tally.Options[i].Votes++
+			tally.Options[i].Voters = append(tally.Options[i].Voters, vote.Voter.String())
+		}
+		if vote.Timestamp.After(tally.UpdatedAt) {
+			tally.UpdatedAt = vote.Timestamp
+		}
+	}
+	return tally, nil
+}
+
+// pollOptions trims the options and rejects duplicates, which votes could not
+// tell apart since they only carry a hash of the option name.
+func pollOptions(raw []string) ([]string, error) {
+	options := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, option := range raw {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return nil, fmt.Errorf("poll options must not be empty")
+		}
+		if seen[option] {
+			return nil, fmt.Errorf("duplicate poll option %q", option)
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+	if len(options) < minPollOptions || len(options) > maxPollOptions {
+		return nil, fmt.Errorf("a poll needs between %d and %d options", minPollOptions, maxPollOptions)
+	}
+	return options, nil
+}
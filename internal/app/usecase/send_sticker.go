@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/media"
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	maxStickerBytes         = 100 << 10
+	maxAnimatedStickerBytes = 500 << 10
+)
+
+type SendStickerInput struct {
+	Session string
+	To      string
+	// Convert turns a PNG or JPEG into a padded 512x512 WebP before sending.
+	Convert bool
+	Media   MediaSource
+	SendOptions
+}
+
+type SendStickerUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSendStickerUsecase(waManager *wa.Manager) *SendStickerUsecase {
+	return &SendStickerUsecase{wa: waManager}
+}
+
+func (u *SendStickerUsecase) Execute(ctx context.Context, in SendStickerInput) (*SendMediaOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := loadMedia(ctx, in.Media)
+	if err != nil {
+		return nil, err
+	}
+
+	data := file.Data
+	switch file.Mimetype {
+	case "image/webp":
+	case "image/png", "image/jpeg":
+		if !in.Convert {
+			return nil, fmt.Errorf("sticker must be webp, set convert to turn %q into one", file.Mimetype)
+		}
+		img, _, err := media.DecodeImage(data)
+		if err != nil {
+			return nil, fmt.Errorf("decode image: %w", err)
+		}
+		data, err = media.StickerWebP(img, maxStickerBytes)
+		if err != nil {
+			return nil, fmt.Errorf("convert sticker: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported sticker type %q", file.Mimetype)
+	}
+
+	info, err := media.ParseWebP(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse webp: %w", err)
+	}
+	if info.Width != media.StickerSize || info.Height != media.StickerSize {
+		return nil, fmt.Errorf("sticker must be %dx%d, got %dx%d", media.StickerSize, media.StickerSize, info.Width, info.Height)
+	}
+
+	limit := maxStickerBytes
+	if info.Animated {
+		limit = maxAnimatedStickerBytes
+	}
+	if len(data) > limit {
+		return nil, fmt.Errorf("sticker is %d bytes, limit is %d", len(data), limit)
+	}
+
+	sticker := &waE2E.StickerMessage{
+		Mimetype:   proto.String("image/webp"),
+		Width:      proto.Uint32(uint32(info.Width)),
+		Height:     proto.Uint32(uint32(info.Height)),
+		IsAnimated: proto.Bool(info.Animated),
+	}
+
+	return sendUploaded(ctx, u.wa, in.Session, recipient, in.SendOptions, data, whatsmeow.MediaImage, func(up whatsmeow.UploadResponse) *waE2E.Message {
+		sticker.URL = proto.String(up.URL)
+		sticker.DirectPath = proto.String(up.DirectPath)
+		sticker.MediaKey = up.MediaKey
+		sticker.FileEncSHA256 = up.FileEncSHA256
+		sticker.FileSHA256 = up.FileSHA256
+		sticker.FileLength = proto.Uint64(up.FileLength)
+		return &waE2E.Message{StickerMessage: sticker}
+	})
+}
@@ -16,6 +16,16 @@ type SendTextInput struct {
 	Session string
 	To      string
 	Message string
+	// Mentions are phone numbers to mention on top of any @<phone> tokens
+	// found in Message. Mentions only apply to group chats.
+	Mentions   []string
+	MentionAll bool
+	// LinkPreview fetches a preview for the first URL in Message. Fields set
+	// in Preview take precedence over what the page provides. An async send
+	// fetches the page in the background.
+	LinkPreview bool
+	Preview     *LinkPreview
+	SendOptions
 }
 
 type SendTextOutput struct {
@@ -24,11 +34,12 @@ type SendTextOutput struct {
 }
 
 type SendTextUsecase struct {
-	wa *wa.Manager
+	wa       *wa.Manager
+	previews *linkPreviewer
 }
 
 func NewSendTextUsecase(waManager *wa.Manager) *SendTextUsecase {
-	return &SendTextUsecase{wa: waManager}
+	return &SendTextUsecase{wa: waManager, previews: newLinkPreviewer()}
 }
 
 func (u *SendTextUsecase) Execute(ctx context.Context, in SendTextInput) (*SendTextOutput, error) {
@@ -36,9 +47,6 @@ func (u *SendTextUsecase) Execute(ctx context.Context, in SendTextInput) (*SendT
 		return nil, err
 	}
 
-	if strings.TrimSpace(in.Session) == "" {
-		return nil, fmt.Errorf("session is required")
-	}
 	if strings.TrimSpace(in.To) == "" {
 		return nil, fmt.Errorf("to is required")
 	}
@@ -46,17 +54,9 @@ func (u *SendTextUsecase) Execute(ctx context.Context, in SendTextInput) (*SendT
 		return nil, fmt.Errorf("message is required")
 	}
 
-	client, err := u.wa.CreateOrGetClientBySession(in.Session)
+	client, err := connectedClient(ctx, u.wa, in.Session)
 	if err != nil {
-		return nil, fmt.Errorf("create client: %w", err)
-	}
-
-	if err := u.wa.EnsureConnected(ctx, in.Session, client); err != nil {
-		return nil, fmt.Errorf("connect: %w", err)
-	}
-
-	if client.Store.ID == nil {
-		return nil, fmt.Errorf("session not logged in")
+		return nil, err
 	}
 
 	recipient, err := parseRecipient(in.To)
@@ -65,12 +65,29 @@ func (u *SendTextUsecase) Execute(ctx context.Context, in SendTextInput) (*SendT
 	}
 
 	msg := &waE2E.Message{Conversation: proto.String(in.Message)}
-	resp, err := client.SendMessage(ctx, recipient, msg)
+	if recipient.Server == types.GroupServer {
+		mentions, err := resolveMentions(ctx, client, recipient, in.Message, in.Mentions, in.MentionAll)
+		if err != nil {
+			return nil, err
+		}
+		if len(mentions) > 0 {
+			contextInfoOf(msg).MentionedJID = mentions
+		}
+	} else if len(in.Mentions) > 0 || in.MentionAll {
+		return nil, fmt.Errorf("mentions are only supported in group chats")
+	}
+
+	if in.LinkPreview || in.Preview != nil {
+		in.prepare = func(ctx context.Context, msg *waE2E.Message) {
+			u.previews.apply(ctx, msg, in.Message, in.Preview, in.LinkPreview)
+		}
+	}
+	resp, err := sendMessage(ctx, u.wa, in.Session, client, recipient, msg, in.SendOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	out := &SendTextOutput{Status: "sent"}
+	out := &SendTextOutput{Status: sentStatus(in.SendOptions)}
 	if resp.ID != "" {
 		out.MessageID = resp.ID
 	}
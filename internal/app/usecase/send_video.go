@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/media"
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type SendVideoInput struct {
+	Session     string
+	To          string
+	Caption     string
+	GifPlayback bool
+	Media       MediaSource
+	// Thumbnail is an optional caller-supplied JPEG or PNG preview.
+	Thumbnail []byte
+	// ViewOnce lets the recipient open the video only once.
+	ViewOnce bool
+	SendOptions
+}
+
+type SendVideoUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSendVideoUsecase(waManager *wa.Manager) *SendVideoUsecase {
+	return &SendVideoUsecase{wa: waManager}
+}
+
+func (u *SendVideoUsecase) Execute(ctx context.Context, in SendVideoInput) (*SendMediaOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.To) == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+	if in.GifPlayback && in.ViewOnce {
+		return nil, fmt.Errorf("gif playback cannot be sent view once")
+	}
+
+	recipient, err := parseRecipient(in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := loadMedia(ctx, in.Media)
+	if err != nil {
+		return nil, err
+	}
+	if file.Mimetype != "video/mp4" {
+		return nil, fmt.Errorf("unsupported video type %q", file.Mimetype)
+	}
+
+	info, err := media.ParseMP4(file.Data)
+	if err != nil {
+		return nil, fmt.Errorf("parse mp4: %w", err)
+	}
+
+	thumb, err := videoThumbnail(in.Thumbnail, info)
+	if err != nil {
+		return nil, fmt.Errorf("thumbnail: %w", err)
+	}
+
+	video := &waE2E.VideoMessage{
+		Mimetype:      proto.String(file.Mimetype),
+		Seconds:       proto.Uint32(uint32(info.Duration.Round(time.Second).Seconds())),
+		GifPlayback:   proto.Bool(in.GifPlayback),
+		JPEGThumbnail: thumb,
+	}
+	if info.Width > 0 && info.Height > 0 {
+		video.Width = proto.Uint32(uint32(info.Width))
+		video.Height = proto.Uint32(uint32(info.Height))
+	}
+	if caption := strings.TrimSpace(in.Caption); caption != "" {
+		video.Caption = proto.String(caption)
+	}
+
+	return sendUploaded(ctx, u.wa, in.Session, recipient, in.SendOptions, file.Data, whatsmeow.MediaVideo, func(up whatsmeow.UploadResponse) *waE2E.Message {
+		video.URL = proto.String(up.URL)
+		video.DirectPath = proto.String(up.DirectPath)
+		video.MediaKey = up.MediaKey
+		video.FileEncSHA256 = up.FileEncSHA256
+		video.FileSHA256 = up.FileSHA256
+		video.FileLength = proto.Uint64(up.FileLength)
+		msg := &waE2E.Message{VideoMessage: video}
+		if in.ViewOnce {
+			return wrapViewOnce(msg)
+		}
+		return msg
+	})
+}
+
+// videoThumbnail prefers the caller's thumbnail, then cover art embedded in
+// the MP4. Frames cannot be decoded without a video codec, so anything else
+// gets a placeholder with the video's aspect ratio.
+func videoThumbnail(supplied []byte, info *media.MP4Info) ([]byte, error) {
+	if len(supplied) > 0 {
+		img, _, err := media.DecodeImage(supplied)
+		if err != nil {
+			return nil, fmt.Errorf("decode thumbnail: %w", err)
+		}
+		return media.JPEGThumbnail(img, media.ThumbnailSize)
+	}
+
+	if len(info.Cover) > 0 {
+		if img, _, err := media.DecodeImage(info.Cover); err == nil {
+			return media.JPEGThumbnail(img, media.ThumbnailSize)
+		}
+	}
+
+	return media.PlaceholderThumbnail(info.Width, info.Height, media.ThumbnailSize)
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+)
+
+type SessionSettingsOutput struct {
+	AutoRead      bool
+	WebhookURL    string
+	WebhookEvents []string
+	// WebhookSigned tells whether deliveries are signed, and
+	// WebhookRotating whether a previous secret still signs them too.
+	WebhookSigned   bool
+	WebhookRotating bool
+	// WebhookSecret is only returned by the update that set it.
+	WebhookSecret string
+}
+
+// minWebhookSecretLen keeps caller-chosen secrets from being guessable.
+const minWebhookSecretLen = 16
+
+// UpdateSettingsInput changes the session settings that are set; nil fields
+// keep their current value.
+type UpdateSettingsInput struct {
+	Session    string
+	AutoRead   *bool
+	WebhookURL *string
+	// WebhookEvents replaces the event filter when non-nil; an empty slice
+	// sends every event.
+	WebhookEvents []string
+	// WebhookSecret starts signing with a new secret; the current one keeps
+	// signing alongside it until RetirePreviousSecret. An empty secret turns
+	// signing off altogether.
+	WebhookSecret *string
+	// GenerateWebhookSecret rotates to a random secret, which is returned.
+	GenerateWebhookSecret bool
+	RetirePreviousSecret  bool
+}
+
+type SessionSettingsUsecase struct {
+	wa *wa.Manager
+}
+
+func NewSessionSettingsUsecase(waManager *wa.Manager) *SessionSettingsUsecase {
+	return &SessionSettingsUsecase{wa: waManager}
+}
+
+func (u *SessionSettingsUsecase) Get(ctx context.Context, session string) (*SessionSettingsOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(session) == "" {
+		return nil, fmt.Errorf("session is required")
+	}
+	if err := requireSession(u.wa, session); err != nil {
+		return nil, err
+	}
+
+	settings, err := u.wa.Settings(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return settingsOutput(settings), nil
+}
+
+func (u *SessionSettingsUsecase) Update(ctx context.Context, in UpdateSettingsInput) (*SessionSettingsOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(in.Session) == "" {
+		return nil, fmt.Errorf("session is required")
+	}
+	if err := requireSession(u.wa, in.Session); err != nil {
+		return nil, err
+	}
+
+	settings, err := u.wa.Settings(ctx, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.AutoRead != nil {
+		settings.AutoRead = *in.AutoRead
+	}
+	if in.WebhookURL != nil {
+		hook, err := parseWebhookURL(*in.WebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		settings.WebhookURL = hook
+	}
+	if in.WebhookEvents != nil {
+		for _, typ := range in.WebhookEvents {
+			if !slices.Contains(wa.EventTypes, typ) {
+				return nil, fmt.Errorf("unknown event type %q, use one of %s", typ, strings.Join(wa.EventTypes, ", "))
+			}
+		}
+		settings.WebhookEvents = in.WebhookEvents
+	}
+
+	var newSecret string
+	switch {
+	case in.GenerateWebhookSecret && in.WebhookSecret != nil:
+		return nil, fmt.Errorf("give either webhook_secret or generate_webhook_secret")
+	case in.GenerateWebhookSecret:
+		newSecret = wa.GenerateWebhookSecret()
+	case in.WebhookSecret != nil:
+		newSecret = strings.TrimSpace(*in.WebhookSecret)
+		if newSecret == "" {
+			settings.WebhookSecret, settings.WebhookPreviousSecret = "", ""
+		} else if len(newSecret) < minWebhookSecretLen {
+			return nil, fmt.Errorf("webhook_secret must be at least %d characters", minWebhookSecretLen)
+		}
+	}
+	if newSecret != "" && newSecret != settings.WebhookSecret {
+		if settings.WebhookSecret != "" {
+			settings.WebhookPreviousSecret = settings.WebhookSecret
+		}
+		settings.WebhookSecret = newSecret
+	}
+	if in.RetirePreviousSecret {
+		settings.WebhookPreviousSecret = ""
+	}
+
+	if err := u.wa.SaveSettings(ctx, in.Session, settings); err != nil {
+		return nil, fmt.Errorf("save settings: %w", err)
+	}
+
+	out := settingsOutput(settings)
+	out.WebhookSecret = newSecret
+	return out, nil
+}
+
+func settingsOutput(settings wa.SessionSettings) *SessionSettingsOutput {
+	return &SessionSettingsOutput{
+		AutoRead:      settings.AutoRead,
+		WebhookURL:    settings.WebhookURL,
+		WebhookEvents: settings.WebhookEvents,
+		// Secrets are never read back; callers keep the one they were given.
+		WebhookSigned:   settings.WebhookSecret != "",
+		WebhookRotating: settings.WebhookPreviousSecret != "",
+	}
+}
+
+// parseWebhookURL accepts an absolute http(s) URL, or an empty one to turn
+// webhooks off.
+func parseWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("webhook_url must be an http or https URL")
+	}
+	return u.String(), nil
+}
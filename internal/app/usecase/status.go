@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultStatusBackground = 0xFF075E54
+	statusTextColor         = 0xFFFFFFFF
+)
+
+// PostTextStatusInput posts a text status. BackgroundColor is "#RRGGBB" or
+// "#AARRGGBB"; Font is a WhatsApp font name such as SYSTEM_BOLD or
+// FB_SCRIPT. Recipients limits the audience; empty means the session's
+// contacts.
+type PostTextStatusInput struct {
+	Session         string
+	Text            string
+	BackgroundColor string
+	Font            string
+	Recipients      []string
+}
+
+type PostMediaStatusInput struct {
+	Session    string
+	Caption    string
+	Media      MediaSource
+	Recipients []string
+}
+
+type StatusOutput struct {
+	Status    string
+	MessageID string
+}
+
+type StatusUsecase struct {
+	wa    *wa.Manager
+	image *SendImageUsecase
+	video *SendVideoUsecase
+}
+
+func NewStatusUsecase(waManager *wa.Manager) *StatusUsecase {
+	return &StatusUsecase{
+		wa:    waManager,
+		image: NewSendImageUsecase(waManager),
+		video: NewSendVideoUsecase(waManager),
+	}
+}
+
+func (u *StatusUsecase) PostText(ctx context.Context, in PostTextStatusInput) (*StatusOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(in.Text)
+	if text == "" {
+		return nil, fmt.Errorf("text is required")
+	}
+	background, err := parseARGB(in.BackgroundColor, defaultStatusBackground)
+	if err != nil {
+		return nil, fmt.Errorf("background_color: %w", err)
+	}
+	font, err := parseStatusFont(in.Font)
+	if err != nil {
+		return nil, err
+	}
+	audience, err := parseAudience(in.Recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(ctx, u.wa, in.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+		Text:           proto.String(text),
+		BackgroundArgb: proto.Uint32(background),
+		TextArgb:       proto.Uint32(statusTextColor),
+		Font:           font.Enum(),
+	}}
+	resp, err := sendMessage(ctx, u.wa, in.Session, client, types.StatusBroadcastJID, msg, SendOptions{statusAudience: audience})
+	if err != nil {
+		return nil, err
+	}
+
+	u.savePost(ctx, in.Session, resp.ID, audience, resp.Timestamp)
+	return &StatusOutput{Status: "posted", MessageID: resp.ID}, nil
+}
+
+func (u *StatusUsecase) PostImage(ctx context.Context, in PostMediaStatusInput) (*StatusOutput, error) {
+	return u.postMedia(ctx, in, func(ctx context.Context, opts SendOptions) (*SendMediaOutput, error) {
+		return u.image.Execute(ctx, SendImageInput{
+			Session:     in.Session,
+			To:          types.StatusBroadcastJID.String(),
+			Caption:     in.Caption,
+			Media:       in.Media,
+			SendOptions: opts,
+		})
+	})
+}
+
+func (u *StatusUsecase) PostVideo(ctx context.Context, in PostMediaStatusInput) (*StatusOutput, error) {
+	return u.postMedia(ctx, in, func(ctx context.Context, opts SendOptions) (*SendMediaOutput, error) {
+		return u.video.Execute(ctx, SendVideoInput{
+			Session:     in.Session,
+			To:          types.StatusBroadcastJID.String(),
+			Caption:     in.Caption,
+			Media:       in.Media,
+			SendOptions: opts,
+		})
+	})
+}
+
+func (u *StatusUsecase) postMedia(ctx context.Context, in PostMediaStatusInput, send func(ctx context.Context, opts SendOptions) (*SendMediaOutput, error)) (*StatusOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	audience, err := parseAudience(in.Recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := send(ctx, SendOptions{statusAudience: audience})
+	if err != nil {
+		return nil, err
+	}
+
+	u.savePost(ctx, in.Session, out.MessageID, audience, time.Now())
+	return &StatusOutput{Status: "posted", MessageID: out.MessageID}, nil
+}
+
+// Delete revokes a status the session posted through the gateway, sending
+// the revoke to the audience the status went to.
+func (u *StatusUsecase) Delete(ctx context.Context, session, id string) (*StatusOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("message id is required")
+	}
+
+	post, found, err := u.wa.GetStatusPost(ctx, session, id)
+	if err != nil {
+		return nil, fmt.Errorf("load status: %w", err)
+	}
+	if !found {
+		return nil, ErrMessageNotFound
+	}
+
+	client, err := connectedClient(ctx, u.wa, session)
+	if err != nil {
+		return nil, err
+	}
+
+	revoke := client.BuildRevoke(types.StatusBroadcastJID, types.EmptyJID, id)
+	if _, err := wa.SendStatus(ctx, client, revoke, post.Audience, whatsmeow.SendRequestExtra{}); err != nil {
+		return nil, err
+	}
+
+	if err := u.wa.DeleteStatusPost(ctx, session, id); err != nil {
+		log.Printf("forget status %s: %v", id, err)
+	}
+	return &StatusOutput{Status: "deleted", MessageID: id}, nil
+}
+
+func (u *StatusUsecase) savePost(ctx context.Context, session, id string, audience []types.JID, ts time.Time) {
+	err := u.wa.SaveStatusPost(ctx, session, wa.StatusPost{ID: id, Audience: audience, Timestamp: ts})
+	if err != nil {
+		log.Printf("store status %s: %v", id, err)
+	}
+}
+
+func parseAudience(recipients []string) ([]types.JID, error) {
+	audience := make([]types.JID, 0, len(recipients))
+	for _, raw := range recipients {
+		jid, err := parseRecipient(raw)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %q: %w", raw, err)
+		}
+		if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+			return nil, fmt.Errorf("recipient %q is not a user", raw)
+		}
+		audience = append(audience, jid)
+	}
+	return audience, nil
+}
+
+// parseARGB reads a "#RRGGBB" or "#AARRGGBB" color; RRGGBB is fully opaque.
+func parseARGB(raw string, fallback uint32) (uint32, error) {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "#")
+	if raw == "" {
+		return fallback, nil
+	}
+	if len(raw) != 6 && len(raw) != 8 {
+		return 0, fmt.Errorf("color must be #RRGGBB or #AARRGGBB")
+	}
+
+	v, err := strconv.ParseUint(raw, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("color must be #RRGGBB or #AARRGGBB")
+	}
+	if len(raw) == 6 {
+		v |= 0xFF000000
+	}
+	return uint32(v), nil
+}
+
+func parseStatusFont(raw string) (waE2E.ExtendedTextMessage_FontType, error) {
+	raw = strings.ToUpper(strings.TrimSpace(raw))
+	if raw == "" {
+		return waE2E.ExtendedTextMessage_SYSTEM, nil
+	}
+	v, ok := waE2E.ExtendedTextMessage_FontType_value[raw]
+	if !ok {
+		return 0, fmt.Errorf("unknown font %q", raw)
+	}
+	return waE2E.ExtendedTextMessage_FontType(v), nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// typingCharsPerSecond is roughly how fast a person types on a phone.
+const typingCharsPerSecond = 8
+
+var (
+	typingMinDelay = 1 * time.Second
+	typingMaxDelay = 8 * time.Second
+)
+
+// ConfigureTyping sets the bounds of the typing delay simulated before
+// sends. It is meant to be called once at startup.
+func ConfigureTyping(minDelay, maxDelay time.Duration) error {
+	if minDelay < 0 || maxDelay < minDelay {
+		return fmt.Errorf("invalid typing delay bounds %s..%s", minDelay, maxDelay)
+	}
+	typingMinDelay, typingMaxDelay = minDelay, maxDelay
+	return nil
+}
+
+// simulateTyping shows the recipient that a message is being typed, or a
+// voice note recorded, and waits about as long as that would take.
+func simulateTyping(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message) error {
+	media := types.ChatPresenceMediaText
+	delay := time.Duration(utf8.RuneCountInString(wa.MessageText(msg))) * time.Second / typingCharsPerSecond
+	if audio := msg.GetAudioMessage(); audio.GetPTT() {
+		media = types.ChatPresenceMediaAudio
+		delay = time.Duration(audio.GetSeconds()) * time.Second
+	}
+	delay = min(max(delay, typingMinDelay), typingMaxDelay)
+
+	if err := client.SendChatPresence(ctx, recipient, types.ChatPresenceComposing, media); err != nil {
+		return fmt.Errorf("send typing presence: %w", err)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		stopTyping(client, recipient)
+		return ctx.Err()
+	}
+}
+
+// showsTyping reports whether chat can show a typing indicator; status posts
+// and channels cannot.
+func showsTyping(chat types.JID) bool {
+	switch chat.Server {
+	case types.DefaultUserServer, types.HiddenUserServer, types.GroupServer:
+		return true
+	}
+	return false
+}
+
+// stopTyping clears the indicator shown by simulateTyping. It runs after the
+// caller's context may be gone, so it uses its own.
+func stopTyping(client *whatsmeow.Client, recipient types.JID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := client.SendChatPresence(ctx, recipient, types.ChatPresencePaused, ""); err != nil {
+		log.Printf("clear typing presence in %s: %v", recipient, err)
+	}
+}
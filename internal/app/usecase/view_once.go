@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// wrapViewOnce puts msg in the container that lets the recipient open its
+// media only once. Voice notes and other audio use the extension container
+// newer clients expect for them.
+func wrapViewOnce(msg *waE2E.Message) *waE2E.Message {
+	switch {
+	case msg.ImageMessage != nil:
+		msg.ImageMessage.ViewOnce = proto.Bool(true)
+	case msg.VideoMessage != nil:
+		msg.VideoMessage.ViewOnce = proto.Bool(true)
+	case msg.AudioMessage != nil:
+		msg.AudioMessage.ViewOnce = proto.Bool(true)
+		return &waE2E.Message{ViewOnceMessageV2Extension: &waE2E.FutureProofMessage{Message: msg}}
+	}
+	return &waE2E.Message{ViewOnceMessageV2: &waE2E.FutureProofMessage{Message: msg}}
+}
+
+// viewOnceContent returns the message inside a view-once container, or nil
+// when msg is not wrapped.
+func viewOnceContent(msg *waE2E.Message) *waE2E.Message {
+	switch {
+	case msg.GetViewOnceMessageV2Extension().GetMessage() != nil:
+		return msg.GetViewOnceMessageV2Extension().GetMessage()
+	case msg.GetViewOnceMessageV2().GetMessage() != nil:
+		return msg.GetViewOnceMessageV2().GetMessage()
+	case msg.GetViewOnceMessage().GetMessage() != nil:
+		return msg.GetViewOnceMessage().GetMessage()
+	}
+	return nil
+}
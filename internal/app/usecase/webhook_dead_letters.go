@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/infra/wa"
+)
+
+const (
+	defaultDeadLetterCount = 50
+	maxDeadLetterCount     = 500
+)
+
+// DeadLetterOutput is a webhook delivery the gateway gave up on. Payload is
+// the event exactly as it would have been posted.
+type DeadLetterOutput struct {
+	ID        int64
+	Session   string
+	EventType string
+	Payload   json.RawMessage
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+	FailedAt  time.Time
+}
+
+type WebhookDeadLettersUsecase struct {
+	wa *wa.Manager
+}
+
+func NewWebhookDeadLettersUsecase(waManager *wa.Manager) *WebhookDeadLettersUsecase {
+	return &WebhookDeadLettersUsecase{wa: waManager}
+}
+
+// List returns the newest dead letters of session, or of every session when
+// session is empty.
+func (u *WebhookDeadLettersUsecase) List(ctx context.Context, session string, count int) ([]DeadLetterOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if count <= 0 {
+		count = defaultDeadLetterCount
+	}
+	if count > maxDeadLetterCount {
+		return nil, fmt.Errorf("count must be at most %d", maxDeadLetterCount)
+	}
+
+	letters, err := u.wa.ListDeadLetters(ctx, session, count)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]DeadLetterOutput, 0, len(letters))
+	for _, d := range letters {
+		out = append(out, DeadLetterOutput{
+			ID:        d.ID,
+			Session:   d.Session,
+			EventType: d.EventType,
+			Payload:   d.Payload,
+			Attempts:  d.Attempts,
+			LastError: d.LastError,
+			CreatedAt: d.CreatedAt,
+			FailedAt:  d.FailedAt,
+		})
+	}
+	return out, nil
+}
+
+// Replay queues dead letters for delivery again: the one with id when id is
+// non-zero, otherwise all of session (or of every session when it is empty).
+func (u *WebhookDeadLettersUsecase) Replay(ctx context.Context, session string, id int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return u.wa.ReplayDeadLetters(ctx, session, deadLetterIDs(id))
+}
+
+// Delete discards dead letters, selected like Replay.
+func (u *WebhookDeadLettersUsecase) Delete(ctx context.Context, session string, id int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return u.wa.DeleteDeadLetters(ctx, session, deadLetterIDs(id))
+}
+
+func deadLetterIDs(id int64) []int64 {
+	if id == 0 {
+		return nil
+	}
+	return []int64{id}
+}
@@ -1,19 +1,37 @@
 package config
 
-import "os"
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	Port       string
 	SQLitePath string
+	// TypingMinDelay and TypingMaxDelay bound how long simulated typing
+	// lasts before a message is sent.
+	TypingMinDelay time.Duration
+	TypingMaxDelay time.Duration
+	// WSAllowedOrigins are the cross-origin host patterns allowed to open
+	// the event WebSocket.
+	WSAllowedOrigins []string
 }
 
 func Load() Config {
 	port := getenv("PORT", "8080")
 	sqlitePath := getenv("SQLITE_PATH", "./data/whatsapp.db")
+	typingMin := getenvDuration("TYPING_MIN_DELAY", time.Second)
+	typingMax := getenvDuration("TYPING_MAX_DELAY", 8*time.Second)
+	wsOrigins := getenvList("WS_ALLOWED_ORIGINS")
 
 	return Config{
-		Port:       port,
-		SQLitePath: sqlitePath,
+		Port:             port,
+		SQLitePath:       sqlitePath,
+		TypingMinDelay:   typingMin,
+		TypingMaxDelay:   typingMax,
+		WSAllowedOrigins: wsOrigins,
 	}
 }
 
@@ -24,3 +42,27 @@ func getenv(key, fallback string) string {
 
 	return fallback
 }
+
+func getenvDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid %s %q, using %s", key, v, fallback)
+		return fallback
+	}
+	return d
+}
+
+func getenvList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
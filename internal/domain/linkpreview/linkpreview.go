@@ -0,0 +1,127 @@
+package linkpreview
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Metadata is what a page says about itself through OpenGraph, Twitter card
+// and plain HTML tags.
+type Metadata struct {
+	URL         string
+	Title       string
+	Description string
+	SiteName    string
+	Image       string
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// FindURL returns the first http(s) URL in text. Punctuation that usually
+// ends a sentence rather than the URL is left out.
+func FindURL(text string) (string, bool) {
+	match := urlPattern.FindString(text)
+	match = strings.TrimRight(match, ".,;:!?'\")]}")
+	if match == "" {
+		return "", false
+	}
+	if _, err := url.Parse(match); err != nil {
+		return "", false
+	}
+	return match, true
+}
+
+// Parse reads the head of an HTML document. OpenGraph tags win over Twitter
+// card tags, which win over <title> and the description meta tag. Relative
+// URLs are resolved against base.
+func Parse(r io.Reader, base *url.URL) Metadata {
+	tags := make(map[string]string)
+	var title strings.Builder
+	inTitle := false
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			break loop
+		case html.TextToken:
+			if inTitle {
+				title.Write(z.Text())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = false
+			case atom.Head:
+				break loop
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = tt == html.StartTagToken
+			case atom.Body:
+				break loop
+			case atom.Meta:
+				if !hasAttr {
+					continue
+				}
+				var key, content string
+				for {
+					attr, val, more := z.TagAttr()
+					switch strings.ToLower(string(attr)) {
+					case "property", "name":
+						key = strings.ToLower(strings.TrimSpace(string(val)))
+					case "content":
+						content = strings.TrimSpace(string(val))
+					}
+					if !more {
+						break
+					}
+				}
+				if _, seen := tags[key]; key != "" && content != "" && !seen {
+					tags[key] = content
+				}
+			}
+		}
+	}
+
+	meta := Metadata{
+		URL:         first(tags["og:url"]),
+		Title:       first(tags["og:title"], tags["twitter:title"], strings.TrimSpace(title.String())),
+		Description: first(tags["og:description"], tags["twitter:description"], tags["description"]),
+		SiteName:    first(tags["og:site_name"]),
+		Image:       first(tags["og:image:secure_url"], tags["og:image"], tags["twitter:image"], tags["twitter:image:src"]),
+	}
+	meta.URL = resolve(base, meta.URL)
+	meta.Image = resolve(base, meta.Image)
+	return meta
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v = strings.Join(strings.Fields(v), " "); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func resolve(base *url.URL, ref string) string {
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,140 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+// ThumbnailSize is the longest side, in pixels, of the JPEG thumbnails
+// embedded in media messages.
+const ThumbnailSize = 72
+
+// MaxImagePixels caps the width times height of images DecodeImage accepts,
+// so a small file cannot make it allocate gigabytes.
+const MaxImagePixels = 50_000_000
+
+// ImageInfo describes a decoded image.
+type ImageInfo struct {
+	Width  int
+	Height int
+	Format string
+}
+
+// DecodeImage decodes a JPEG, PNG or GIF image. The header is checked
+// against MaxImagePixels before any pixels are decoded.
+func DecodeImage(data []byte) (image.Image, ImageInfo, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ImageInfo{}, err
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return nil, ImageInfo{}, fmt.Errorf("image is %dx%d, limit is %d pixels", cfg.Width, cfg.Height, MaxImagePixels)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ImageInfo{}, err
+	}
+	b := img.Bounds()
+	return img, ImageInfo{Width: b.Dx(), Height: b.Dy(), Format: format}, nil
+}
+
+// JPEGThumbnail scales img down so its longest side is at most maxSide and
+// encodes it as JPEG. Transparent areas are flattened onto white.
+func JPEGThumbnail(img image.Image, maxSide int) ([]byte, error) {
+	if img == nil {
+		return nil, errors.New("image is required")
+	}
+
+	thumb := Resize(img, maxSide)
+
+	flat := image.NewRGBA(thumb.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), thumb, thumb.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: 75}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Resize scales img so that its longest side is at most maxSide, keeping the
+// aspect ratio. Images that already fit are returned unchanged. Each target
+// pixel is the average of the source pixels it covers.
+func Resize(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+
+	tw, th := maxSide, maxSide
+	if w > h {
+		th = max(1, h*maxSide/w)
+	} else {
+		tw = max(1, w*maxSide/h)
+	}
+
+	return scale(img, tw, th)
+}
+
+func scale(img image.Image, tw, th int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+
+	for y := 0; y < th; y++ {
+		y0 := b.Min.Y + y*h/th
+		y1 := max(y0+1, b.Min.Y+(y+1)*h/th)
+		for x := 0; x < tw; x++ {
+			x0 := b.Min.X + x*w/tw
+			x1 := max(x0+1, b.Min.X+(x+1)*w/tw)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
+
+// PlaceholderThumbnail returns a plain dark JPEG with the aspect ratio of a
+// width x height frame, for media whose frames cannot be decoded here.
+func PlaceholderThumbnail(width, height, maxSide int) ([]byte, error) {
+	if width <= 0 || height <= 0 {
+		width, height = 16, 9
+	}
+	frame := image.NewUniform(color.RGBA{R: 32, G: 32, B: 32, A: 255})
+	return JPEGThumbnail(&boundedImage{Uniform: frame, rect: image.Rect(0, 0, width, height)}, maxSide)
+}
+
+type boundedImage struct {
+	*image.Uniform
+	rect image.Rectangle
+}
+
+func (b *boundedImage) Bounds() image.Rectangle { return b.rect }
@@ -0,0 +1,44 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestDecodeImageRejectsHugeDimensions(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// Claim 100000x100000 in the IHDR chunk, which follows the 8-byte
+	// signature, and fix up its CRC.
+	ihdr := data[8 : 8+8+13+4]
+	binary.BigEndian.PutUint32(ihdr[8:12], 100000)
+	binary.BigEndian.PutUint32(ihdr[12:16], 100000)
+	binary.BigEndian.PutUint32(ihdr[21:25], crc32.ChecksumIEEE(ihdr[4:21]))
+
+	if _, _, err := DecodeImage(data); err == nil {
+		t.Fatal("DecodeImage accepted a 100000x100000 image")
+	}
+}
+
+func TestDecodeImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 30, 20))); err != nil {
+		t.Fatal(err)
+	}
+
+	_, info, err := DecodeImage(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != (ImageInfo{Width: 30, Height: 20, Format: "png"}) {
+		t.Fatalf("info = %+v", info)
+	}
+}
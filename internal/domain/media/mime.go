@@ -0,0 +1,46 @@
+package media
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// DetectMimetype picks the most specific mimetype it can for data. A declared
+// type wins unless it is empty or generic, then content sniffing, then the
+// filename extension.
+func DetectMimetype(data []byte, filename, declared string) string {
+	declared = baseMimetype(declared)
+	if declared != "" && declared != "application/octet-stream" {
+		return declared
+	}
+
+	sniffed := baseMimetype(http.DetectContentType(data))
+	if sniffed != "application/octet-stream" && sniffed != "text/plain" {
+		if sniffed == "application/ogg" {
+			return "audio/ogg"
+		}
+		return sniffed
+	}
+
+	if ext := filepath.Ext(filename); ext != "" {
+		if byExt := baseMimetype(mime.TypeByExtension(ext)); byExt != "" {
+			return byExt
+		}
+	}
+
+	return sniffed
+}
+
+func baseMimetype(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(raw)
+	if err != nil {
+		return strings.ToLower(raw)
+	}
+	return mt
+}
@@ -0,0 +1,163 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// MP4Info describes an MP4 file as read from its moov box.
+type MP4Info struct {
+	Duration time.Duration
+	Width    int
+	Height   int
+	// Cover is the embedded cover art (moov/udta/meta/ilst/covr), if any.
+	Cover []byte
+}
+
+type mp4Box struct {
+	typ  string
+	body []byte
+}
+
+// ParseMP4 reads the duration from mvhd, the frame size from the first video
+// track's tkhd and any embedded cover art. Sample data is never touched.
+func ParseMP4(data []byte) (*MP4Info, error) {
+	moov, ok := findBox(data, "moov")
+	if !ok {
+		return nil, errors.New("mp4 has no moov box")
+	}
+
+	info := &MP4Info{}
+
+	mvhd, ok := findBox(moov, "mvhd")
+	if !ok || len(mvhd) < 4 {
+		return nil, errors.New("mp4 has no mvhd box")
+	}
+	timescale, duration, err := parseMvhd(mvhd)
+	if err != nil {
+		return nil, err
+	}
+	if timescale > 0 {
+		info.Duration = time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+	}
+
+	for _, trak := range childBoxes(moov) {
+		if trak.typ != "trak" {
+			continue
+		}
+		if !isVideoTrack(trak.body) {
+			continue
+		}
+		if tkhd, ok := findBox(trak.body, "tkhd"); ok {
+			info.Width, info.Height = parseTkhdSize(tkhd)
+		}
+		break
+	}
+
+	if ilst, ok := findPath(moov, "udta", "meta", "ilst"); ok {
+		if covr, ok := findBox(ilst, "covr"); ok {
+			if d, ok := findBox(covr, "data"); ok && len(d) > 8 {
+				info.Cover = d[8:]
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func parseMvhd(b []byte) (uint32, uint64, error) {
+	version := b[0]
+	switch {
+	case version == 1 && len(b) >= 32:
+		return binary.BigEndian.Uint32(b[20:24]), binary.BigEndian.Uint64(b[24:32]), nil
+	case version == 0 && len(b) >= 20:
+		return binary.BigEndian.Uint32(b[12:16]), uint64(binary.BigEndian.Uint32(b[16:20])), nil
+	}
+	return 0, 0, errors.New("invalid mvhd box")
+}
+
+func parseTkhdSize(b []byte) (int, int) {
+	// Width and height are the last 8 bytes, as 16.16 fixed point.
+	if len(b) < 84 {
+		return 0, 0
+	}
+	end := len(b)
+	if b[0] == 0 {
+		end = 84
+	} else if len(b) >= 96 {
+		end = 96
+	}
+	w := binary.BigEndian.Uint32(b[end-8 : end-4])
+	h := binary.BigEndian.Uint32(b[end-4 : end])
+	return int(w >> 16), int(h >> 16)
+}
+
+func isVideoTrack(trak []byte) bool {
+	hdlr, ok := findPath(trak, "mdia", "hdlr")
+	if !ok || len(hdlr) < 12 {
+		return false
+	}
+	return string(hdlr[8:12]) == "vide"
+}
+
+func findPath(data []byte, path ...string) ([]byte, bool) {
+	cur := data
+	for _, typ := range path {
+		next, ok := findBox(cur, typ)
+		if !ok {
+			return nil, false
+		}
+		// meta is a full box: skip version and flags before its children.
+		if typ == "meta" && len(next) >= 4 && !looksLikeBox(next) {
+			next = next[4:]
+		}
+		cur = next
+	}
+	return cur, true
+}
+
+func looksLikeBox(b []byte) bool {
+	if len(b) < 8 {
+		return false
+	}
+	size := binary.BigEndian.Uint32(b[0:4])
+	return size >= 8 && int(size) <= len(b) && bytes.IndexFunc(b[4:8], func(r rune) bool { return r < 0x20 || r > 0x7e }) == -1
+}
+
+func findBox(data []byte, typ string) ([]byte, bool) {
+	for _, box := range childBoxes(data) {
+		if box.typ == typ {
+			return box.body, true
+		}
+	}
+	return nil, false
+}
+
+func childBoxes(data []byte) []mp4Box {
+	var out []mp4Box
+	for pos := 0; len(data)-pos >= 8; {
+		size := uint64(binary.BigEndian.Uint32(data[pos : pos+4]))
+		typ := string(data[pos+4 : pos+8])
+		header := uint64(8)
+
+		switch size {
+		case 0:
+			size = uint64(len(data) - pos)
+		case 1:
+			if len(data)-pos < 16 {
+				return out
+			}
+			size = binary.BigEndian.Uint64(data[pos+8 : pos+16])
+			header = 16
+		}
+		if size < header || size > uint64(len(data)-pos) {
+			return out
+		}
+
+		out = append(out, mp4Box{typ: typ, body: data[pos+int(header) : pos+int(size)]})
+		pos += int(size)
+	}
+	return out
+}
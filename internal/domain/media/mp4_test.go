@@ -0,0 +1,130 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func box(typ string, body ...[]byte) []byte {
+	b := bytes.Join(body, nil)
+	out := make([]byte, 8, 8+len(b))
+	binary.BigEndian.PutUint32(out, uint32(8+len(b)))
+	copy(out[4:], typ)
+	return append(out, b...)
+}
+
+func mvhdV0(timescale, duration uint32) []byte {
+	b := make([]byte, 100)
+	binary.BigEndian.PutUint32(b[12:16], timescale)
+	binary.BigEndian.PutUint32(b[16:20], duration)
+	return box("mvhd", b)
+}
+
+func mvhdV1(timescale uint32, duration uint64) []byte {
+	b := make([]byte, 112)
+	b[0] = 1
+	binary.BigEndian.PutUint32(b[20:24], timescale)
+	binary.BigEndian.PutUint64(b[24:32], duration)
+	return box("mvhd", b)
+}
+
+func trak(handler string, width, height int) []byte {
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:80], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:84], uint32(height)<<16)
+
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:12], handler)
+
+	return box("trak", box("tkhd", tkhd), box("mdia", box("hdlr", hdlr)))
+}
+
+func coverArt(img []byte) []byte {
+	data := box("data", make([]byte, 8), img)
+	return box("udta", box("meta", make([]byte, 4), box("ilst", box("covr", data))))
+}
+
+func TestParseMP4(t *testing.T) {
+	cover := []byte("\xff\xd8cover")
+	tests := []struct {
+		name string
+		data []byte
+		want MP4Info
+	}{
+		{
+			name: "video after audio track with cover",
+			data: bytes.Join([][]byte{
+				box("ftyp", []byte("isom")),
+				box("moov", mvhdV0(1000, 12500), trak("soun", 0, 0), trak("vide", 1280, 720), coverArt(cover)),
+				box("mdat", make([]byte, 32)),
+			}, nil),
+			want: MP4Info{Duration: 12500 * time.Millisecond, Width: 1280, Height: 720, Cover: cover},
+		},
+		{
+			name: "moov after mdat with version 1 mvhd",
+			data: append(box("mdat", make([]byte, 16)), box("moov", mvhdV1(90000, 90000*3), trak("vide", 640, 360))...),
+			want: MP4Info{Duration: 3 * time.Second, Width: 640, Height: 360},
+		},
+		{
+			name: "audio only",
+			data: box("moov", mvhdV0(44100, 44100), trak("soun", 0, 0)),
+			want: MP4Info{Duration: time.Second},
+		},
+		{
+			name: "zero timescale",
+			data: box("moov", mvhdV0(0, 100), trak("vide", 320, 240)),
+			want: MP4Info{Width: 320, Height: 240},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseMP4(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Duration != tt.want.Duration || info.Width != tt.want.Width || info.Height != tt.want.Height || !bytes.Equal(info.Cover, tt.want.Cover) {
+				t.Fatalf("ParseMP4() = %+v, want %+v", *info, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMP4LargeSizeBox(t *testing.T) {
+	moov := box("moov", mvhdV0(1, 7))
+	// Rewrite moov with a 64-bit size: size field 1, then the real size.
+	large := make([]byte, 16, 16+len(moov)-8)
+	binary.BigEndian.PutUint32(large, 1)
+	copy(large[4:8], "moov")
+	binary.BigEndian.PutUint64(large[8:16], uint64(len(moov)+8))
+	large = append(large, moov[8:]...)
+
+	info, err := ParseMP4(large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Duration != 7*time.Second {
+		t.Fatalf("Duration = %v, want 7s", info.Duration)
+	}
+}
+
+func TestParseMP4Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"no moov", box("ftyp", []byte("isom"))},
+		{"no mvhd", box("moov", trak("vide", 1, 1))},
+		{"short mvhd", box("moov", box("mvhd", make([]byte, 10)))},
+		{"moov larger than file", box("moov", mvhdV0(1, 1))[:50]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMP4(tt.data); err == nil {
+				t.Fatal("ParseMP4() succeeded, want an error")
+			}
+		})
+	}
+}
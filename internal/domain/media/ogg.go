@@ -0,0 +1,137 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// WaveformSamples is the number of bars WhatsApp draws for a voice note.
+const WaveformSamples = 64
+
+const opusSampleRate = 48000
+
+// maxOpusDuration is the longest stream ParseOggOpus accepts. The granule
+// position is whatever the file claims, and no voice note comes close.
+const maxOpusDuration = 24 * time.Hour
+
+// OpusInfo describes an Ogg/Opus stream.
+type OpusInfo struct {
+	Duration time.Duration
+	// Waveform holds WaveformSamples values between 0 and 100.
+	Waveform []byte
+}
+
+var (
+	oggCapture = []byte("OggS")
+	opusHead   = []byte("OpusHead")
+)
+
+// ParseOggOpus walks the Ogg pages of an Opus stream. The duration comes from
+// the final granule position minus the encoder pre-skip. The waveform is
+// estimated from the size of the audio packets, which for Opus tracks
+// loudness closely enough for a preview without decoding the audio.
+func ParseOggOpus(data []byte) (*OpusInfo, error) {
+	var (
+		packets     [][]byte
+		partial     []byte
+		lastGranule uint64
+	)
+
+	for pos := 0; pos < len(data); {
+		if len(data)-pos < 27 || !bytes.Equal(data[pos:pos+4], oggCapture) {
+			return nil, errors.New("invalid ogg page")
+		}
+		header := data[pos : pos+27]
+		granule := binary.LittleEndian.Uint64(header[6:14])
+		segCount := int(header[26])
+		if len(data)-pos < 27+segCount {
+			return nil, errors.New("truncated ogg page")
+		}
+		segments := data[pos+27 : pos+27+segCount]
+		body := pos + 27 + segCount
+
+		for _, size := range segments {
+			end := body + int(size)
+			if end > len(data) {
+				return nil, errors.New("truncated ogg page")
+			}
+			partial = append(partial, data[body:end]...)
+			body = end
+			if size < 255 {
+				packets = append(packets, partial)
+				partial = nil
+			}
+		}
+
+		if granule != ^uint64(0) {
+			lastGranule = granule
+		}
+		pos = body
+	}
+
+	if len(packets) == 0 || !bytes.HasPrefix(packets[0], opusHead) {
+		return nil, errors.New("not an opus stream")
+	}
+	if len(packets[0]) < 19 {
+		return nil, errors.New("invalid opus header")
+	}
+	preSkip := uint64(binary.LittleEndian.Uint16(packets[0][10:12]))
+
+	// Packet 0 is OpusHead and packet 1 is OpusTags; audio follows.
+	var audio [][]byte
+	if len(packets) > 2 {
+		audio = packets[2:]
+	}
+
+	samples := uint64(0)
+	if lastGranule > preSkip {
+		samples = lastGranule - preSkip
+	}
+
+	seconds := samples / opusSampleRate
+	if seconds > uint64(maxOpusDuration/time.Second) {
+		return nil, errors.New("ogg duration out of range")
+	}
+
+	return &OpusInfo{
+		Duration: time.Duration(seconds)*time.Second + time.Duration(samples%opusSampleRate)*time.Second/opusSampleRate,
+		Waveform: packetWaveform(audio),
+	}, nil
+}
+
+func packetWaveform(packets [][]byte) []byte {
+	out := make([]byte, WaveformSamples)
+	if len(packets) == 0 {
+		return out
+	}
+
+	levels := make([]float64, WaveformSamples)
+	peak := 0.0
+	for i := range levels {
+		start := i * len(packets) / WaveformSamples
+		end := (i + 1) * len(packets) / WaveformSamples
+		if end <= start {
+			end = start + 1
+		}
+		if end > len(packets) {
+			end = len(packets)
+		}
+
+		total := 0
+		for _, p := range packets[start:end] {
+			total += len(p)
+		}
+		levels[i] = float64(total) / float64(end-start)
+		peak = max(peak, levels[i])
+	}
+
+	if peak == 0 {
+		return out
+	}
+	for i, level := range levels {
+		out[i] = byte(level / peak * 100)
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// oggPage builds an Ogg page holding whole packets. The CRC is left zero;
+// ParseOggOpus does not check it.
+func oggPage(granule uint64, packets ...[]byte) []byte {
+	var lacing, body []byte
+	for _, p := range packets {
+		n := len(p)
+		for ; n >= 255; n -= 255 {
+			lacing = append(lacing, 255)
+		}
+		lacing = append(lacing, byte(n))
+		body = append(body, p...)
+	}
+
+	header := make([]byte, 27)
+	copy(header, "OggS")
+	binary.LittleEndian.PutUint64(header[6:14], granule)
+	header[26] = byte(len(lacing))
+	return append(append(header, lacing...), body...)
+}
+
+func opusHeadPacket(preSkip uint16) []byte {
+	p := make([]byte, 19)
+	copy(p, "OpusHead")
+	p[8] = 1
+	p[9] = 1
+	binary.LittleEndian.PutUint16(p[10:12], preSkip)
+	return p
+}
+
+func TestParseOggOpus(t *testing.T) {
+	stream := bytes.Join([][]byte{
+		oggPage(0, opusHeadPacket(312)),
+		oggPage(0, []byte("OpusTags")),
+		oggPage(^uint64(0), make([]byte, 10), make([]byte, 600)),
+		oggPage(2*opusSampleRate+312, make([]byte, 300), make([]byte, 40)),
+	}, nil)
+
+	info, err := ParseOggOpus(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Duration != 2*time.Second {
+		t.Errorf("Duration = %v, want 2s", info.Duration)
+	}
+	if len(info.Waveform) != WaveformSamples {
+		t.Fatalf("len(Waveform) = %d, want %d", len(info.Waveform), WaveformSamples)
+	}
+	var peak byte
+	for _, v := range info.Waveform {
+		if v > 100 {
+			t.Fatalf("waveform value %d above 100", v)
+		}
+		peak = max(peak, v)
+	}
+	if peak != 100 {
+		t.Errorf("waveform peak = %d, want 100", peak)
+	}
+	// The 600-byte packet is the loudest and sits in the second quarter.
+	if info.Waveform[WaveformSamples/4] != 100 || info.Waveform[0] == 100 {
+		t.Errorf("waveform does not follow packet sizes: %v", info.Waveform)
+	}
+}
+
+func TestParseOggOpusWithoutAudio(t *testing.T) {
+	stream := append(oggPage(0, opusHeadPacket(0)), oggPage(0, []byte("OpusTags"))...)
+
+	info, err := ParseOggOpus(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Duration != 0 {
+		t.Errorf("Duration = %v, want 0", info.Duration)
+	}
+	if !bytes.Equal(info.Waveform, make([]byte, WaveformSamples)) {
+		t.Errorf("Waveform = %v, want silence", info.Waveform)
+	}
+}
+
+func TestParseOggOpusErrors(t *testing.T) {
+	page := oggPage(0, opusHeadPacket(0))
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not ogg", []byte("RIFF0000WAVEfmt ")},
+		{"truncated header", page[:20]},
+		{"truncated body", page[:len(page)-1]},
+		{"not opus", oggPage(0, []byte("\x01vorbis0000000000000000"))},
+		{"short opus head", oggPage(0, []byte("OpusHead\x01"))},
+		{"duration out of range", append(page, oggPage(1<<62, make([]byte, 10))...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseOggOpus(tt.data); err == nil {
+				t.Fatal("ParseOggOpus() succeeded, want an error")
+			}
+		})
+	}
+}
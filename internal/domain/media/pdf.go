@@ -0,0 +1,18 @@
+package media
+
+import (
+	"bytes"
+	"regexp"
+)
+
+var pdfPageRe = regexp.MustCompile(`/Type\s*/Page\b`)
+
+// PDFPageCount counts the page objects in a PDF without parsing it fully.
+// It returns 0 when data is not a PDF or the pages live in compressed object
+// streams, where counting would need a real parser.
+func PDFPageCount(data []byte) int {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return 0
+	}
+	return len(pdfPageRe.FindAllIndex(data, -1))
+}
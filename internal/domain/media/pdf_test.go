@@ -0,0 +1,30 @@
+package media
+
+import "testing"
+
+func TestPDFPageCount(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{"not a pdf", "/Type /Page /Type /Page", 0},
+		{"empty", "", 0},
+		{"no pages", "%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj", 0},
+		{
+			"pages tree is not a page",
+			"%PDF-1.4\n1 0 obj << /Type /Pages /Kids [2 0 R 3 0 R] /Count 2 >> endobj\n" +
+				"2 0 obj << /Type /Page /Parent 1 0 R >> endobj\n" +
+				"3 0 obj << /Type/Page/Parent 1 0 R >> endobj",
+			2,
+		},
+		{"page followed by delimiter", "%PDF-1.7\n<</Type /Page/MediaBox [0 0 612 792]>>", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PDFPageCount([]byte(tt.data)); got != tt.want {
+				t.Fatalf("PDFPageCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
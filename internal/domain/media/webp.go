@@ -0,0 +1,164 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// StickerSize is the width and height WhatsApp requires for stickers.
+const StickerSize = 512
+
+// WebPInfo describes a WebP image as read from its RIFF chunks.
+type WebPInfo struct {
+	Width    int
+	Height   int
+	Animated bool
+}
+
+// ParseWebP reads the canvas size and animation flag of a WebP file without
+// decoding it.
+func ParseWebP(data []byte) (*WebPInfo, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errors.New("not a webp file")
+	}
+
+	info := &WebPInfo{}
+	sized := false
+	for pos := 12; len(data)-pos >= 8; {
+		typ := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		start := pos + 8
+		if size < 0 || size > len(data)-start {
+			return nil, errors.New("truncated webp chunk")
+		}
+		chunk := data[start : start+size]
+
+		switch typ {
+		case "VP8X":
+			if len(chunk) < 10 {
+				return nil, errors.New("invalid VP8X chunk")
+			}
+			info.Animated = chunk[0]&0x02 != 0
+			info.Width = int(uint32(chunk[4])|uint32(chunk[5])<<8|uint32(chunk[6])<<16) + 1
+			info.Height = int(uint32(chunk[7])|uint32(chunk[8])<<8|uint32(chunk[9])<<16) + 1
+			sized = true
+		case "ANIM", "ANMF":
+			info.Animated = true
+		case "VP8L":
+			if !sized {
+				if len(chunk) < 5 || chunk[0] != 0x2f {
+					return nil, errors.New("invalid VP8L chunk")
+				}
+				bits := binary.LittleEndian.Uint32(chunk[1:5])
+				info.Width = int(bits&0x3fff) + 1
+				info.Height = int(bits>>14&0x3fff) + 1
+				sized = true
+			}
+		case "VP8 ":
+			if !sized {
+				if len(chunk) < 10 || !bytes.Equal(chunk[3:6], []byte{0x9d, 0x01, 0x2a}) {
+					return nil, errors.New("invalid VP8 chunk")
+				}
+				info.Width = int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3fff)
+				info.Height = int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3fff)
+				sized = true
+			}
+		}
+
+		// Chunks are padded to an even size.
+		pos = start + size + size&1
+	}
+
+	if !sized {
+		return nil, errors.New("webp has no image chunk")
+	}
+	return info, nil
+}
+
+// stickerSteps are tried in order until a converted sticker fits: full size
+// with every colour first, then fewer colour levels, then a smaller picture
+// on the same canvas. The encoder is lossless, and dropping colour levels is
+// what shrinks a photo the most.
+var stickerSteps = []struct {
+	side int
+	bits uint
+}{
+	{StickerSize, 8}, {StickerSize, 6}, {StickerSize, 5}, {StickerSize, 4},
+	{448, 4}, {384, 4}, {320, 4}, {256, 4}, {256, 3},
+}
+
+// StickerWebP scales img to fit a StickerSize square, centres it on a
+// transparent canvas and encodes it as WebP of at most maxBytes, trading
+// colour depth and then size for bytes as needed.
+func StickerWebP(img image.Image, maxBytes int) ([]byte, error) {
+	b := img.Bounds()
+	if b.Dx() == 0 || b.Dy() == 0 {
+		return nil, errors.New("image is empty")
+	}
+
+	var (
+		side   int
+		scaled *image.NRGBA
+		size   int
+	)
+	for _, step := range stickerSteps {
+		if step.side != side {
+			side = step.side
+			scaled = stickerCanvas(img, side)
+		}
+
+		var buf bytes.Buffer
+		if err := nativewebp.Encode(&buf, posterize(scaled, step.bits), nil); err != nil {
+			return nil, err
+		}
+		if buf.Len() <= maxBytes {
+			return buf.Bytes(), nil
+		}
+		size = buf.Len()
+	}
+	return nil, fmt.Errorf("sticker is still %d bytes at its smallest, limit is %d", size, maxBytes)
+}
+
+// stickerCanvas fits img into a side x side square centred on a transparent
+// StickerSize canvas.
+func stickerCanvas(img image.Image, side int) *image.NRGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	tw, th := side, side
+	if w > h {
+		th = max(1, h*side/w)
+	} else {
+		tw = max(1, w*side/h)
+	}
+	scaled := scale(img, tw, th)
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, StickerSize, StickerSize))
+	offset := image.Pt((StickerSize-tw)/2, (StickerSize-th)/2)
+	draw.Draw(canvas, scaled.Bounds().Add(offset), scaled, image.Point{}, draw.Src)
+	return canvas
+}
+
+// posterize keeps the top bits of every colour channel, spreading them over
+// the full range again. Alpha is left alone.
+func posterize(img *image.NRGBA, bits uint) *image.NRGBA {
+	if bits >= 8 {
+		return img
+	}
+	out := image.NewNRGBA(img.Rect)
+	mask := byte(0xff << (8 - bits))
+	for i := 0; i < len(img.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			v := img.Pix[i+c] & mask
+			out.Pix[i+c] = v | v>>bits | v>>(2*bits)
+		}
+		out.Pix[i+3] = img.Pix[i+3]
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"testing"
+)
+
+func riff(chunks ...[]byte) []byte {
+	body := append([]byte("WEBP"), bytes.Join(chunks, nil)...)
+	out := make([]byte, 8, 8+len(body))
+	copy(out, "RIFF")
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(body)))
+	return append(out, body...)
+}
+
+func chunk(typ string, body []byte) []byte {
+	out := make([]byte, 8, 8+len(body)+1)
+	copy(out, typ)
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(body)))
+	out = append(out, body...)
+	if len(body)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+func vp8x(flags byte, width, height int) []byte {
+	b := make([]byte, 10)
+	b[0] = flags
+	w, h := width-1, height-1
+	b[4], b[5], b[6] = byte(w), byte(w>>8), byte(w>>16)
+	b[7], b[8], b[9] = byte(h), byte(h>>8), byte(h>>16)
+	return chunk("VP8X", b)
+}
+
+func vp8l(width, height int) []byte {
+	b := make([]byte, 5)
+	b[0] = 0x2f
+	binary.LittleEndian.PutUint32(b[1:5], uint32(width-1)|uint32(height-1)<<14)
+	return chunk("VP8L", b)
+}
+
+func vp8(width, height int) []byte {
+	b := make([]byte, 10)
+	copy(b[3:6], []byte{0x9d, 0x01, 0x2a})
+	binary.LittleEndian.PutUint16(b[6:8], uint16(width))
+	binary.LittleEndian.PutUint16(b[8:10], uint16(height))
+	return chunk("VP8 ", b)
+}
+
+func TestParseWebP(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want WebPInfo
+	}{
+		{"lossy", riff(vp8(512, 512)), WebPInfo{Width: 512, Height: 512}},
+		{"lossless", riff(vp8l(512, 300)), WebPInfo{Width: 512, Height: 300}},
+		{"extended still", riff(vp8x(0x10, 512, 512), chunk("ALPH", []byte{1, 2, 3}), vp8(100, 100)), WebPInfo{Width: 512, Height: 512}},
+		{"extended animated flag", riff(vp8x(0x02, 512, 512)), WebPInfo{Width: 512, Height: 512, Animated: true}},
+		{"animation chunks", riff(vp8x(0, 512, 512), chunk("ANIM", make([]byte, 6)), chunk("ANMF", make([]byte, 16))), WebPInfo{Width: 512, Height: 512, Animated: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseWebP(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *info != tt.want {
+				t.Fatalf("ParseWebP() = %+v, want %+v", *info, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWebPErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not riff", []byte("\x89PNG\r\n\x1a\n0000")},
+		{"riff but not webp", append([]byte("RIFF\x04\x00\x00\x00WAVE"), vp8(1, 1)...)},
+		{"no image chunk", riff(chunk("EXIF", []byte("xx")))},
+		{"truncated chunk", riff(vp8(512, 512))[:25]},
+		{"bad vp8l signature", riff(chunk("VP8L", make([]byte, 5)))},
+		{"bad vp8 start code", riff(chunk("VP8 ", make([]byte, 10)))},
+		{"short vp8x", riff(chunk("VP8X", make([]byte, 4)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseWebP(tt.data); err == nil {
+				t.Fatal("ParseWebP() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestStickerWebPFitsLimit(t *testing.T) {
+	// A noisy gradient compresses about as badly as a photo.
+	rng := rand.New(rand.NewPCG(1, 2))
+	photo := image.NewRGBA(image.Rect(0, 0, 800, 600))
+	for y := range 600 {
+		for x := range 800 {
+			n := uint8(rng.IntN(24))
+			photo.Set(x, y, color.RGBA{uint8(x/4) + n, uint8(y/3) + n, uint8((x + y) / 6), 255})
+		}
+	}
+
+	const limit = 100 << 10
+	data, err := StickerWebP(photo, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > limit {
+		t.Fatalf("sticker is %d bytes, limit is %d", len(data), limit)
+	}
+
+	info, err := ParseWebP(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Width != StickerSize || info.Height != StickerSize || info.Animated {
+		t.Fatalf("sticker is %+v, want a still %dx%d", *info, StickerSize, StickerSize)
+	}
+}
+
+func TestStickerWebPKeepsSmallStickersIntact(t *testing.T) {
+	logo := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	for y := range 50 {
+		for x := range 100 {
+			logo.Set(x, y, color.RGBA{R: 200, G: uint8(x), B: 7, A: 255})
+		}
+	}
+
+	data, err := StickerWebP(logo, 100<<10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Skipf("no webp decoder registered: %v", err)
+	}
+	// Full colour survives when the first attempt already fits.
+	if got := color.RGBAModel.Convert(img.At(256, 256)).(color.RGBA); got.B != 7 {
+		t.Fatalf("pixel = %v, want blue 7", got)
+	}
+}
+
+func TestStickerWebPTooLarge(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	noise := image.NewRGBA(image.Rect(0, 0, 512, 512))
+	for i := range noise.Pix {
+		noise.Pix[i] = uint8(rng.IntN(256))
+	}
+	if _, err := StickerWebP(noise, 1<<10); err == nil {
+		t.Fatal("StickerWebP() fit noise into 1KB")
+	}
+}
@@ -0,0 +1,76 @@
+package vcard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fardannozami/whatsapp-gateway/internal/domain/phone"
+)
+
+type Phone struct {
+	Number string
+	// Type is a vCard TEL type such as CELL, WORK or HOME. Defaults to CELL.
+	Type string
+}
+
+type Contact struct {
+	Name   string
+	Phones []Phone
+	Email  string
+	Org    string
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// Build renders c as a vCard 3.0. Every phone carries the waid parameter
+// WhatsApp uses to link the card to an account.
+func Build(c Contact) (string, error) {
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		return "", errors.New("contact name is required")
+	}
+	if len(c.Phones) == 0 {
+		return "", errors.New("contact needs at least one phone")
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\r\n")
+	b.WriteString("VERSION:3.0\r\n")
+	fmt.Fprintf(&b, "N:;%s;;;\r\n", escaper.Replace(name))
+	fmt.Fprintf(&b, "FN:%s\r\n", escaper.Replace(name))
+	if org := strings.TrimSpace(c.Org); org != "" {
+		fmt.Fprintf(&b, "ORG:%s;\r\n", escaper.Replace(org))
+	}
+
+	for _, p := range c.Phones {
+		number, err := phone.Normalize(p.Number)
+		if err != nil {
+			return "", fmt.Errorf("phone %q: %w", p.Number, err)
+		}
+		typ := strings.ToUpper(strings.TrimSpace(p.Type))
+		if typ == "" {
+			typ = "CELL"
+		}
+		if !isToken(typ) {
+			return "", fmt.Errorf("invalid phone type %q", p.Type)
+		}
+		fmt.Fprintf(&b, "TEL;type=%s;type=VOICE;waid=%s:+%s\r\n", typ, number, number)
+	}
+
+	if email := strings.TrimSpace(c.Email); email != "" {
+		fmt.Fprintf(&b, "EMAIL;type=INTERNET:%s\r\n", escaper.Replace(email))
+	}
+
+	b.WriteString("END:VCARD")
+	return b.String(), nil
+}
+
+func isToken(s string) bool {
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return s != ""
+}
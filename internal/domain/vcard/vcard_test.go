@@ -0,0 +1,72 @@
+package vcard
+
+import "testing"
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact Contact
+		want    string
+	}{
+		{
+			name:    "minimal",
+			contact: Contact{Name: "Budi", Phones: []Phone{{Number: "+62 812-2982-2979"}}},
+			want: "BEGIN:VCARD\r\nVERSION:3.0\r\nN:;Budi;;;\r\nFN:Budi\r\n" +
+				"TEL;type=CELL;type=VOICE;waid=6281229822979:+6281229822979\r\nEND:VCARD",
+		},
+		{
+			name: "every field",
+			contact: Contact{
+				Name:   "  Siti, Toko; Jaya  ",
+				Phones: []Phone{{Number: "6281229822979", Type: "work"}, {Number: "6285700001111", Type: "HOME"}},
+				Email:  "siti@example.com",
+				Org:    `Toko\Jaya`,
+			},
+			want: "BEGIN:VCARD\r\nVERSION:3.0\r\n" +
+				`N:;Siti\, Toko\; Jaya;;;` + "\r\n" +
+				`FN:Siti\, Toko\; Jaya` + "\r\n" +
+				`ORG:Toko\\Jaya;` + "\r\n" +
+				"TEL;type=WORK;type=VOICE;waid=6281229822979:+6281229822979\r\n" +
+				"TEL;type=HOME;type=VOICE;waid=6285700001111:+6285700001111\r\n" +
+				"EMAIL;type=INTERNET:siti@example.com\r\nEND:VCARD",
+		},
+		{
+			name:    "newlines are escaped",
+			contact: Contact{Name: "Line\nBreak", Phones: []Phone{{Number: "6281229822979"}}},
+			want: "BEGIN:VCARD\r\nVERSION:3.0\r\n" + `N:;Line\nBreak;;;` + "\r\n" + `FN:Line\nBreak` + "\r\n" +
+				"TEL;type=CELL;type=VOICE;waid=6281229822979:+6281229822979\r\nEND:VCARD",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Build(tt.contact)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Build() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	phones := []Phone{{Number: "6281229822979"}}
+	tests := []struct {
+		name    string
+		contact Contact
+	}{
+		{"no name", Contact{Name: "  ", Phones: phones}},
+		{"no phones", Contact{Name: "Budi"}},
+		{"bad number", Contact{Name: "Budi", Phones: []Phone{{Number: "12ab"}}}},
+		{"short number", Contact{Name: "Budi", Phones: []Phone{{Number: "1234"}}}},
+		{"injected type", Contact{Name: "Budi", Phones: []Phone{{Number: "6281229822979", Type: "CELL:x\r\nTEL"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Build(tt.contact); err == nil {
+				t.Fatal("Build() succeeded, want an error")
+			}
+		})
+	}
+}
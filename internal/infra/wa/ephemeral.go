@@ -0,0 +1,192 @@
+package wa
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ChatEphemeral is the disappearing-messages timer of a chat as last seen by
+// the gateway. A zero Timer means messages do not disappear.
+type ChatEphemeral struct {
+	Timer     time.Duration
+	SettingAt time.Time
+}
+
+// WhatsApp has no way to ask for a private chat's timer, so the gateway keeps
+// track of it from the settings it makes and the messages it receives.
+const ephemeralSchema = `
+CREATE TABLE IF NOT EXISTS gateway_chat_ephemeral (
+	chat       TEXT    PRIMARY KEY,
+	timer      INTEGER NOT NULL,
+	setting_at INTEGER NOT NULL
+);
+`
+
+// defaultEphemeralKey is the row holding the session's default timer for new
+// chats. No chat has an empty JID.
+const defaultEphemeralKey = ""
+
+// ChatEphemeral returns the chat's timer; found is false when the gateway has
+// never seen the chat's setting.
+func (m *Manager) ChatEphemeral(ctx context.Context, session string, chat types.JID) (*ChatEphemeral, bool, error) {
+	return m.loadEphemeral(ctx, session, chat.ToNonAD().String())
+}
+
+// SaveChatEphemeral records the chat's timer unless a newer setting is
+// already known.
+func (m *Manager) SaveChatEphemeral(ctx context.Context, session string, chat types.JID, setting ChatEphemeral) error {
+	return m.saveEphemeral(ctx, session, chat.ToNonAD().String(), setting)
+}
+
+// DefaultEphemeral returns the timer the session's new chats start with;
+// found is false when the gateway has never set one.
+func (m *Manager) DefaultEphemeral(ctx context.Context, session string) (*ChatEphemeral, bool, error) {
+	return m.loadEphemeral(ctx, session, defaultEphemeralKey)
+}
+
+func (m *Manager) SaveDefaultEphemeral(ctx context.Context, session string, setting ChatEphemeral) error {
+	return m.saveEphemeral(ctx, session, defaultEphemeralKey, setting)
+}
+
+func (m *Manager) loadEphemeral(ctx context.Context, session, key string) (*ChatEphemeral, bool, error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var timer, settingAt int64
+	err = db.QueryRowContext(ctx, `
+		SELECT timer, setting_at FROM gateway_chat_ephemeral WHERE chat = ?`,
+		key,
+	).Scan(&timer, &settingAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &ChatEphemeral{
+		Timer:     time.Duration(timer) * time.Second,
+		SettingAt: time.Unix(settingAt, 0),
+	}, true, nil
+}
+
+func (m *Manager) saveEphemeral(ctx context.Context, session, key string, setting ChatEphemeral) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO gateway_chat_ephemeral (chat, timer, setting_at) VALUES (?, ?, ?)
+		ON CONFLICT (chat) DO UPDATE SET timer = excluded.timer, setting_at = excluded.setting_at
+		WHERE excluded.setting_at >= gateway_chat_ephemeral.setting_at`,
+		key, int64(setting.Timer/time.Second), setting.SettingAt.Unix(),
+	)
+	return err
+}
+
+// trackEphemeral follows timer changes announced in the chat. Ordinary
+// messages count too, as senders stamp them with the timer they use.
+func (m *Manager) trackEphemeral(session string, evt *events.Message) {
+	var (
+		expiration uint32
+		settingAt  int64
+	)
+	if pm := evt.Message.GetProtocolMessage(); pm != nil {
+		if pm.GetType() != waE2E.ProtocolMessage_EPHEMERAL_SETTING {
+			return
+		}
+		expiration, settingAt = pm.GetEphemeralExpiration(), pm.GetEphemeralSettingTimestamp()
+	} else {
+		ci := contentContextInfo(evt.Message)
+		if ci.GetExpiration() == 0 {
+			m.trackChatWithoutTimer(session, evt)
+			return
+		}
+		expiration, settingAt = ci.GetExpiration(), ci.GetEphemeralSettingTimestamp()
+	}
+	if settingAt == 0 {
+		settingAt = evt.Info.Timestamp.Unix()
+	}
+
+	err := m.SaveChatEphemeral(context.Background(), session, canonicalChat(evt.Info.MessageSource), ChatEphemeral{
+		Timer:     time.Duration(expiration) * time.Second,
+		SettingAt: time.Unix(settingAt, 0),
+	})
+	if err != nil {
+		m.log.Warnf("store ephemeral setting of %s in %s: %v", evt.Info.Chat, session, err)
+	}
+}
+
+// trackChatWithoutTimer records the timer of a direct chat the gateway knows
+// nothing about as off once a message arrives in it unstamped, so the chat is
+// not taken for a new one that starts with the session's default. A known
+// setting is left alone, as not every kind of message carries the timer.
+func (m *Manager) trackChatWithoutTimer(session string, evt *events.Message) {
+	chat := canonicalChat(evt.Info.MessageSource)
+	if chat.Server != types.DefaultUserServer && chat.Server != types.HiddenUserServer {
+		return
+	}
+	if messageType(evt.Message) == "" {
+		return
+	}
+
+	db, err := m.sessionDB(session)
+	if err != nil {
+		m.log.Warnf("store ephemeral setting of %s in %s: %v", evt.Info.Chat, session, err)
+		return
+	}
+	_, err = db.ExecContext(context.Background(), `
+		INSERT INTO gateway_chat_ephemeral (chat, timer, setting_at) VALUES (?, 0, ?)
+		ON CONFLICT (chat) DO NOTHING`,
+		chat.ToNonAD().String(), evt.Info.Timestamp.Unix(),
+	)
+	if err != nil {
+		m.log.Warnf("store ephemeral setting of %s in %s: %v", evt.Info.Chat, session, err)
+	}
+}
+
+func (m *Manager) trackGroupEphemeral(session string, evt *events.GroupInfo) {
+	if evt.Ephemeral == nil {
+		return
+	}
+
+	timer := time.Duration(0)
+	if evt.Ephemeral.IsEphemeral {
+		timer = time.Duration(evt.Ephemeral.DisappearingTimer) * time.Second
+	}
+	err := m.SaveChatEphemeral(context.Background(), session, evt.JID, ChatEphemeral{
+		Timer:     timer,
+		SettingAt: evt.Timestamp,
+	})
+	if err != nil {
+		m.log.Warnf("store ephemeral setting of %s in %s: %v", evt.JID, session, err)
+	}
+}
+
+// contentContextInfo returns the ContextInfo of whichever content msg
+// carries, or nil.
+func contentContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	var ci *waE2E.ContextInfo
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return true
+		}
+		field := fd.Message().Fields().ByName("contextInfo")
+		if field == nil || field.Kind() != protoreflect.MessageKind || !v.Message().Has(field) {
+			return true
+		}
+		ci, _ = v.Message().Get(field).Message().Interface().(*waE2E.ContextInfo)
+		return ci == nil
+	})
+	return ci
+}
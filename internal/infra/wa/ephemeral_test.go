@@ -0,0 +1,75 @@
+package wa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	walog "go.mau.fi/whatsmeow/util/log"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTrackEphemeral(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(t.TempDir(), walog.Noop)
+	if _, err := m.getContainer("s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	week := 7 * 24 * time.Hour
+	known := types.NewJID("6281200000001", types.DefaultUserServer)
+	if err := m.SaveChatEphemeral(ctx, "s1", known, ChatEphemeral{Timer: week, SettingAt: time.Unix(1000, 0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	message := func(chat types.JID, msg *waE2E.Message) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{Chat: chat, Sender: chat, IsGroup: chat.Server == types.GroupServer},
+				ID:            "MSG1",
+				Timestamp:     time.Unix(2000, 0),
+			},
+			Message: msg,
+		}
+	}
+	text := &waE2E.Message{Conversation: proto.String("hi")}
+	stamped := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+		Text:        proto.String("hi"),
+		ContextInfo: &waE2E.ContextInfo{Expiration: proto.Uint32(86400), EphemeralSettingTimestamp: proto.Int64(1500)},
+	}}
+	reaction := &waE2E.Message{ReactionMessage: &waE2E.ReactionMessage{Text: proto.String("👍")}}
+
+	tests := []struct {
+		name      string
+		chat      types.JID
+		msg       *waE2E.Message
+		wantFound bool
+		wantTimer time.Duration
+	}{
+		{"unstamped message in an unknown chat", types.NewJID("6281200000002", types.DefaultUserServer), text, true, 0},
+		{"stamped message in an unknown chat", types.NewJID("6281200000003", types.DefaultUserServer), stamped, true, 24 * time.Hour},
+		{"unstamped message in a known chat", known, text, true, week},
+		{"reaction in an unknown chat", types.NewJID("6281200000004", types.DefaultUserServer), reaction, false, 0},
+		{"unstamped message in an unknown group", types.NewJID("120363000000000001", types.GroupServer), text, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.trackEphemeral("s1", message(tt.chat, tt.msg))
+
+			setting, found, err := m.ChatEphemeral(ctx, "s1", tt.chat)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("found = %v, want %v", found, tt.wantFound)
+			}
+			if found && setting.Timer != tt.wantTimer {
+				t.Errorf("timer = %v, want %v", setting.Timer, tt.wantTimer)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package wa
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// eventLogCapacity is how many of its latest events a session keeps for
+	// clients resuming a stream.
+	eventLogCapacity = 1000
+	// eventLogTrimEvery is how many events are logged between trims, so the
+	// log holds up to this many more than its capacity.
+	eventLogTrimEvery = 100
+)
+
+// The event log is a ring: AUTOINCREMENT keeps IDs growing after the oldest
+// rows are trimmed, so a client's last ID always means the same event. The
+// epoch is drawn when the log is first used; a session deleted and paired
+// again starts a new log, whose IDs count from 1 again under a new epoch.
+const eventLogSchema = `
+CREATE TABLE IF NOT EXISTS gateway_event_log (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	type      TEXT    NOT NULL,
+	timestamp INTEGER NOT NULL,
+	data      BLOB    NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS gateway_event_log_epoch (
+	id    INTEGER PRIMARY KEY CHECK (id = 1),
+	epoch TEXT    NOT NULL
+);
+`
+
+// EventID identifies an event in its session's event log, written
+// "<epoch>-<seq>". Seq orders the events of one log and Epoch tells logs
+// apart, so an ID from a session's earlier life is never taken for one of
+// its current log.
+type EventID struct {
+	Epoch string
+	Seq   int64
+}
+
+// IsZero reports whether the event was published without being logged.
+func (id EventID) IsZero() bool {
+	return id.Seq == 0
+}
+
+func (id EventID) String() string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Epoch + "-" + strconv.FormatInt(id.Seq, 10)
+}
+
+func (id EventID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// ParseEventID parses an ID written by EventID.String. A bare number, as IDs
+// were written before logs had epochs, parses with an empty Epoch and so
+// belongs to no current log.
+func ParseEventID(s string) (EventID, error) {
+	epoch, seq, ok := strings.Cut(s, "-")
+	if !ok {
+		epoch, seq = "", s
+	}
+	n, err := strconv.ParseInt(seq, 10, 64)
+	if err != nil || n <= 0 || (ok && epoch == "") {
+		return EventID{}, fmt.Errorf("invalid event id %q", s)
+	}
+	return EventID{Epoch: epoch, Seq: n}, nil
+}
+
+// sessionEventLog is what publishing a session's events needs. Its fields
+// are only touched with mu held.
+type sessionEventLog struct {
+	mu sync.Mutex
+	// epoch is the epoch of db, the store it was read from.
+	db    *sql.DB
+	epoch string
+}
+
+// eventLogEpoch returns the epoch of the log in db, drawing it on first use.
+func eventLogEpoch(ctx context.Context, db *sql.DB) (string, error) {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO gateway_event_log_epoch (id, epoch) VALUES (1, ?)
+		ON CONFLICT (id) DO NOTHING`,
+		rand.Text()[:8],
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var epoch string
+	err = db.QueryRowContext(ctx, `SELECT epoch FROM gateway_event_log_epoch WHERE id = 1`).Scan(&epoch)
+	return epoch, err
+}
+
+// logEvent gives evt the next ID of its session's event log, with log.mu
+// held. Events of a session whose store is not open, such as one just
+// deleted, are published without an ID.
+func (m *Manager) logEvent(log *sessionEventLog, evt *Event) {
+	m.mu.RLock()
+	db, ok := m.dbs[evt.Session]
+	m.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	if log.db != db {
+		epoch, err := eventLogEpoch(ctx, db)
+		if err != nil {
+			m.log.Warnf("load event log epoch of %s: %v", evt.Session, err)
+			return
+		}
+		log.db, log.epoch = db, epoch
+	}
+
+	data, err := json.Marshal(evt.Data)
+	if err != nil {
+		m.log.Warnf("encode %s event of %s: %v", evt.Type, evt.Session, err)
+		return
+	}
+
+	res, err := db.ExecContext(ctx, `
+		INSERT INTO gateway_event_log (type, timestamp, data) VALUES (?, ?, ?)`,
+		evt.Type, evt.Timestamp.UnixMilli(), data,
+	)
+	if err != nil {
+		m.log.Warnf("log %s event of %s: %v", evt.Type, evt.Session, err)
+		return
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	evt.ID = EventID{Epoch: log.epoch, Seq: id}
+
+	if id%eventLogTrimEvery != 0 {
+		return
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM gateway_event_log WHERE id <= ?`, id-eventLogCapacity); err != nil {
+		m.log.Warnf("trim event log of %s: %v", evt.Session, err)
+	}
+}
+
+// EventsSince returns the logged events of session after the one with ID
+// after, or the whole log when after is zero, oldest first. complete is false
+// when some of them have already been trimmed from the log. An after from
+// another log, as clients hold once a session is deleted and paired again,
+// returns no events and complete false.
+func (m *Manager) EventsSince(ctx context.Context, session string, after EventID) (events []Event, complete bool, err error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return nil, false, err
+	}
+
+	epoch, err := eventLogEpoch(ctx, db)
+	if err != nil {
+		return nil, false, err
+	}
+	if !after.IsZero() && after.Epoch != epoch {
+		return nil, false, nil
+	}
+
+	var oldest, newest sql.NullInt64
+	err = db.QueryRowContext(ctx, `SELECT MIN(id), MAX(id) FROM gateway_event_log`).Scan(&oldest, &newest)
+	if err != nil {
+		return nil, false, err
+	}
+	complete = after.IsZero() || (oldest.Valid && oldest.Int64 <= after.Seq+1 && after.Seq <= newest.Int64)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, type, timestamp, data FROM gateway_event_log
+		WHERE id > ? ORDER BY id`,
+		after.Seq,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			evt  = Event{Session: session}
+			seq  int64
+			ts   int64
+			data []byte
+		)
+		if err := rows.Scan(&seq, &evt.Type, &ts, &data); err != nil {
+			return nil, false, err
+		}
+		evt.ID = EventID{Epoch: epoch, Seq: seq}
+		evt.Timestamp = time.UnixMilli(ts)
+		evt.Data = json.RawMessage(data)
+		events = append(events, evt)
+	}
+	return events, complete, rows.Err()
+}
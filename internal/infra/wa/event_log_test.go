@@ -0,0 +1,131 @@
+package wa
+
+import (
+	"context"
+	"testing"
+
+	walog "go.mau.fi/whatsmeow/util/log"
+)
+
+func TestParseEventID(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    EventID
+		wantErr bool
+	}{
+		{in: "K3QX7ZPA-42", want: EventID{Epoch: "K3QX7ZPA", Seq: 42}},
+		{in: "120", want: EventID{Seq: 120}},
+		{in: "", wantErr: true},
+		{in: "-42", wantErr: true},
+		{in: "K3QX7ZPA-", wantErr: true},
+		{in: "K3QX7ZPA-0", wantErr: true},
+		{in: "K3QX7ZPA--1", wantErr: true},
+		{in: "K3QX7ZPA-x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseEventID(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseEventID(%q) = %v, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEventID(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseEventID(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if tt.want.Epoch != "" && got.String() != tt.in {
+				t.Errorf("String() = %q, want %q", got.String(), tt.in)
+			}
+		})
+	}
+}
+
+func TestEventsSince(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(t.TempDir(), walog.Noop)
+	if _, err := m.getContainer("s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	events, unsubscribe := m.Subscribe(eventLogCapacity + 2*eventLogTrimEvery)
+	defer unsubscribe()
+
+	// One trim past capacity drops the first eventLogTrimEvery events.
+	total := eventLogCapacity + eventLogTrimEvery + 10
+	ids := make([]EventID, 0, total)
+	for i := 0; i < total; i++ {
+		m.publish(Event{Session: "s1", Type: EventConnection, Data: ConnectionEvent{Status: "connected"}})
+		ids = append(ids, (<-events).ID)
+	}
+	for i, id := range ids {
+		if id.Seq != int64(i+1) || id.Epoch != ids[0].Epoch {
+			t.Fatalf("event %d has ID %v, want %s-%d", i, id, ids[0].Epoch, i+1)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		after        EventID
+		wantComplete bool
+		wantFirst    int64
+		wantCount    int
+	}{
+		{"whole log", EventID{}, true, eventLogTrimEvery + 1, eventLogCapacity + 10},
+		{"oldest kept is next", ids[eventLogTrimEvery-1], true, eventLogTrimEvery + 1, eventLogCapacity + 10},
+		{"recent", ids[total-4], true, int64(total - 2), 3},
+		{"up to date", ids[total-1], true, 0, 0},
+		{"trimmed", ids[eventLogTrimEvery-2], false, eventLogTrimEvery + 1, eventLogCapacity + 10},
+		{"ahead of log", EventID{Epoch: ids[0].Epoch, Seq: int64(total + 5)}, false, 0, 0},
+		{"other epoch", EventID{Epoch: "OTHER", Seq: 5}, false, 0, 0},
+		{"no epoch", EventID{Seq: 5}, false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, complete, err := m.EventsSince(ctx, "s1", tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if complete != tt.wantComplete {
+				t.Errorf("complete = %v, want %v", complete, tt.wantComplete)
+			}
+			if len(got) != tt.wantCount {
+				t.Fatalf("got %d events, want %d", len(got), tt.wantCount)
+			}
+			if len(got) > 0 && got[0].ID.Seq != tt.wantFirst {
+				t.Errorf("first event is %v, want seq %d", got[0].ID, tt.wantFirst)
+			}
+		})
+	}
+
+	t.Run("session paired again", func(t *testing.T) {
+		if _, err := m.DeleteSession(ctx, "s1"); err != nil {
+			t.Fatal(err)
+		}
+		// Deleting publishes the session's status.
+		for len(events) > 0 {
+			<-events
+		}
+		if _, err := m.getContainer("s1"); err != nil {
+			t.Fatal(err)
+		}
+		m.publish(Event{Session: "s1", Type: EventConnection, Data: ConnectionEvent{Status: "connected"}})
+		id := (<-events).ID
+		if id.Seq != 1 || id.Epoch == ids[0].Epoch {
+			t.Fatalf("first event of the new log has ID %v", id)
+		}
+
+		got, complete, err := m.EventsSince(ctx, "s1", ids[total-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if complete || len(got) != 0 {
+			t.Errorf("ID of the old log got %d events, complete %v; want none, incomplete", len(got), complete)
+		}
+	})
+}
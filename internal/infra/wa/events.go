@@ -0,0 +1,188 @@
+package wa
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	EventMessage      = "message"
+	EventReaction     = "reaction"
+	EventReceipt      = "receipt"
+	EventPresence     = "presence"
+	EventChatPresence = "chat_presence"
+	EventConnection   = "connection"
+	EventStatus       = "session_status"
+	EventPairing      = "pairing"
+)
+
+// EventTypes lists every event type the gateway publishes.
+var EventTypes = []string{
+	EventMessage, EventReaction, EventPollVote, EventReceipt,
+	EventPresence, EventChatPresence, EventConnection, EventStatus, EventPairing,
+}
+
+// Event is a normalized, JSON-friendly gateway event for one session.
+type Event struct {
+	// ID orders the events of a session. It is zero for events published
+	// while the session's store was closed.
+	ID        EventID   `json:"id,omitzero"`
+	Session   string    `json:"session"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+}
+
+type MessageEvent struct {
+	Chat      string `json:"chat"`
+	Sender    string `json:"sender"`
+	PushName  string `json:"push_name"`
+	FromMe    bool   `json:"is_from_me"`
+	IsGroup   bool   `json:"is_group"`
+	MessageID string `json:"message_id"`
+	Type      string `json:"type"`
+	// Text is the message text, or the caption of media.
+	Text  string      `json:"text,omitempty"`
+	Media *MediaEvent `json:"media,omitempty"`
+	// ViewOnce media can be opened only once by the recipient.
+	ViewOnce bool `json:"view_once"`
+}
+
+// MediaEvent describes the media attached to a message. Fields that do not
+// apply to the media type are left out.
+type MediaEvent struct {
+	MimeType string `json:"mime_type"`
+	FileName string `json:"file_name,omitempty"`
+	Size     uint64 `json:"size"`
+	Seconds  uint32 `json:"seconds,omitempty"`
+	Width    uint32 `json:"width,omitempty"`
+	Height   uint32 `json:"height,omitempty"`
+}
+
+type ReactionEvent struct {
+	Chat      string `json:"chat"`
+	Sender    string `json:"sender"`
+	FromMe    bool   `json:"is_from_me"`
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji"`
+	Removed   bool   `json:"removed"`
+}
+
+// ReceiptEvent reports that messages were delivered to, read or played by
+// Sender. Type is delivered, read or played.
+type ReceiptEvent struct {
+	Chat       string   `json:"chat"`
+	Sender     string   `json:"sender"`
+	IsGroup    bool     `json:"is_group"`
+	MessageIDs []string `json:"message_ids"`
+	Type       string   `json:"type"`
+}
+
+// PresenceEvent reports a contact going online or offline. WhatsApp only
+// sends them for contacts whose presence the session has subscribed to.
+type PresenceEvent struct {
+	JID       string `json:"jid"`
+	Available bool   `json:"available"`
+	LastSeen  int64  `json:"last_seen,omitempty"`
+}
+
+// ChatPresenceEvent reports someone typing (State composing, Media audio
+// when recording a voice note) or stopping (State paused) in a chat.
+type ChatPresenceEvent struct {
+	Chat   string `json:"chat"`
+	Sender string `json:"sender"`
+	State  string `json:"state"`
+	Media  string `json:"media,omitempty"`
+}
+
+// ConnectionEvent reports the session connecting, disconnecting or being
+// logged out from the phone.
+type ConnectionEvent struct {
+	Status string `json:"status"`
+}
+
+// StatusEvent reports the gateway's status of the session changing, e.g.
+// from connecting to working.
+type StatusEvent struct {
+	Status string `json:"status"`
+}
+
+// PairingEvent reports progress pairing the session by phone number: a
+// new code (Status code), a failed attempt (Status failed) or success
+// (Status paired).
+type PairingEvent struct {
+	Status    string `json:"status"`
+	Code      string `json:"pairing_code,omitempty"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+	Error     string `json:"error,omitempty"`
+	RetryAt   int64  `json:"retry_at,omitempty"`
+}
+
+type eventHub struct {
+	// logs serialize publishing per session, so every subscriber sees a
+	// session's events in ID order without sessions waiting on each other's
+	// event logs.
+	logsMu sync.Mutex
+	logs   map[string]*sessionEventLog
+
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]chan Event
+}
+
+func (h *eventHub) sessionLog(session string) *sessionEventLog {
+	h.logsMu.Lock()
+	defer h.logsMu.Unlock()
+
+	log, ok := h.logs[session]
+	if !ok {
+		log = &sessionEventLog{}
+		h.logs[session] = log
+	}
+	return log
+}
+
+// Subscribe returns a channel receiving every event published from now on,
+// and a function that unsubscribes and closes it. Events are dropped for a
+// subscriber whose buffer is full rather than blocking the session.
+func (m *Manager) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	m.events.mu.Lock()
+	id := m.events.nextID
+	m.events.nextID++
+	m.events.subs[id] = ch
+	m.events.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.events.mu.Lock()
+			delete(m.events.subs, id)
+			m.events.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (m *Manager) publish(evt Event) {
+	if evt.Timestamp.IsZero() {
+		evt.Timestamp = time.Now()
+	}
+
+	log := m.events.sessionLog(evt.Session)
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	m.logEvent(log, &evt)
+	m.enqueueWebhook(context.Background(), evt)
+
+	m.events.mu.RLock()
+	defer m.events.mu.RUnlock()
+	for _, ch := range m.events.subs {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package wa
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// handleMessage stores an incoming message and announces it, unless it is a
+// protocol message or otherwise has nothing to show.
+func (m *Manager) handleMessage(session string, evt *events.Message) {
+	m.storeIncoming(session, evt)
+
+	typ := messageType(evt.Message)
+	if typ == "" {
+		return
+	}
+
+	m.publish(Event{
+		Session:   session,
+		Type:      EventMessage,
+		Timestamp: evt.Info.Timestamp,
+		Data: MessageEvent{
+			Chat:      canonicalChat(evt.Info.MessageSource).String(),
+			Sender:    evt.Info.Sender.ToNonAD().String(),
+			PushName:  evt.Info.PushName,
+			FromMe:    evt.Info.IsFromMe,
+			IsGroup:   evt.Info.IsGroup,
+			MessageID: evt.Info.ID,
+			Type:      typ,
+			Text:      MessageText(evt.Message),
+			Media:     messageMedia(evt.Message),
+			ViewOnce:  evt.IsViewOnce,
+		},
+	})
+}
+
+// messageType names the kind of content msg carries, or returns "" for
+// messages without user-visible content.
+func messageType(msg *waE2E.Message) string {
+	switch {
+	case msg == nil:
+		return ""
+	case msg.Conversation != nil, msg.ExtendedTextMessage != nil:
+		return "text"
+	case msg.ImageMessage != nil:
+		return "image"
+	case msg.VideoMessage != nil:
+		return "video"
+	case msg.AudioMessage != nil:
+		if msg.AudioMessage.GetPTT() {
+			return "voice"
+		}
+		return "audio"
+	case msg.DocumentMessage != nil, msg.DocumentWithCaptionMessage != nil:
+		return "document"
+	case msg.StickerMessage != nil:
+		return "sticker"
+	case msg.LocationMessage != nil:
+		return "location"
+	case msg.LiveLocationMessage != nil:
+		return "live_location"
+	case msg.ContactMessage != nil, msg.ContactsArrayMessage != nil:
+		return "contact"
+	case PollCreationOf(msg) != nil:
+		return "poll"
+	}
+	return ""
+}
+
+// MessageText returns the text of msg, or the caption of its media.
+func MessageText(msg *waE2E.Message) string {
+	switch {
+	case msg == nil:
+		return ""
+	case msg.Conversation != nil:
+		return msg.GetConversation()
+	case msg.ExtendedTextMessage != nil:
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.ImageMessage != nil:
+		return msg.GetImageMessage().GetCaption()
+	case msg.VideoMessage != nil:
+		return msg.GetVideoMessage().GetCaption()
+	case msg.DocumentMessage != nil:
+		return msg.GetDocumentMessage().GetCaption()
+	case msg.DocumentWithCaptionMessage != nil:
+		return MessageText(msg.GetDocumentWithCaptionMessage().GetMessage())
+	case PollCreationOf(msg) != nil:
+		return PollCreationOf(msg).GetName()
+	}
+	return ""
+}
+
+// messageMedia describes the media msg carries, or returns nil when it has
+// none.
+func messageMedia(msg *waE2E.Message) *MediaEvent {
+	if doc := msg.GetDocumentWithCaptionMessage().GetMessage(); doc != nil {
+		msg = doc
+	}
+
+	switch {
+	case msg.ImageMessage != nil:
+		img := msg.GetImageMessage()
+		return &MediaEvent{MimeType: img.GetMimetype(), Size: img.GetFileLength(), Width: img.GetWidth(), Height: img.GetHeight()}
+	case msg.VideoMessage != nil:
+		vid := msg.GetVideoMessage()
+		return &MediaEvent{MimeType: vid.GetMimetype(), Size: vid.GetFileLength(), Seconds: vid.GetSeconds(), Width: vid.GetWidth(), Height: vid.GetHeight()}
+	case msg.AudioMessage != nil:
+		aud := msg.GetAudioMessage()
+		return &MediaEvent{MimeType: aud.GetMimetype(), Size: aud.GetFileLength(), Seconds: aud.GetSeconds()}
+	case msg.DocumentMessage != nil:
+		doc := msg.GetDocumentMessage()
+		return &MediaEvent{MimeType: doc.GetMimetype(), FileName: doc.GetFileName(), Size: doc.GetFileLength()}
+	case msg.StickerMessage != nil:
+		stk := msg.GetStickerMessage()
+		return &MediaEvent{MimeType: stk.GetMimetype(), Size: stk.GetFileLength(), Width: stk.GetWidth(), Height: stk.GetHeight()}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package wa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+type LiveLocation struct {
+	ID             string
+	Session        string
+	To             types.JID
+	Latitude       float64
+	Longitude      float64
+	AccuracyMeters uint32
+	Caption        string
+	Sequence       int64
+	Interval       time.Duration
+	StartedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+// LiveLocationSender sends an update of a live location share with the
+// current position.
+type LiveLocationSender func(ctx context.Context, loc LiveLocation) error
+
+// liveLocationFinalTimeout bounds the last update sent when a share ends.
+const liveLocationFinalTimeout = 30 * time.Second
+
+// errLiveLocationStopped ends a share stopped by the caller. Unlike a share
+// replaced by a new one or cut off with its session, it still gets a last
+// update.
+var errLiveLocationStopped = errors.New("live location stopped")
+
+type liveShare struct {
+	loc    LiveLocation
+	cancel context.CancelCauseFunc
+}
+
+func liveKey(session, id string) string {
+	return session + "/" + id
+}
+
+// StartLiveLocation keeps sending updates of loc through send every
+// loc.Interval until it is stopped or loc.ExpiresAt passes, and then sends a
+// last one.
+func (m *Manager) StartLiveLocation(loc LiveLocation, send LiveLocationSender) error {
+	key, err := normalizeSession(loc.Session)
+	if err != nil {
+		return err
+	}
+	if loc.ID == "" {
+		return fmt.Errorf("live location id is required")
+	}
+	if loc.Interval <= 0 {
+		return fmt.Errorf("live location interval must be positive")
+	}
+	loc.Session = key
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	share := &liveShare{loc: loc, cancel: cancel}
+
+	m.liveMu.Lock()
+	if prev, ok := m.live[liveKey(key, loc.ID)]; ok {
+		prev.cancel(nil)
+	}
+	m.live[liveKey(key, loc.ID)] = share
+	m.liveMu.Unlock()
+
+	go m.runLiveLocation(ctx, share, send)
+	return nil
+}
+
+func (m *Manager) runLiveLocation(ctx context.Context, share *liveShare, send LiveLocationSender) {
+	key := liveKey(share.loc.Session, share.loc.ID)
+	ticker := time.NewTicker(share.loc.Interval)
+	defer ticker.Stop()
+	expire := time.NewTimer(time.Until(share.loc.ExpiresAt))
+	defer expire.Stop()
+	defer func() {
+		m.liveMu.Lock()
+		if m.live[key] == share {
+			delete(m.live, key)
+		}
+		m.liveMu.Unlock()
+		share.cancel(nil)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if context.Cause(ctx) == errLiveLocationStopped {
+				m.finishLiveLocation(share, send)
+			}
+			return
+		case <-expire.C:
+			m.finishLiveLocation(share, send)
+			return
+		case <-ticker.C:
+			m.liveMu.Lock()
+			if m.live[key] != share {
+				m.liveMu.Unlock()
+				return
+			}
+			share.loc.Sequence++
+			loc := share.loc
+			m.liveMu.Unlock()
+
+			if err := send(ctx, loc); err != nil && ctx.Err() == nil {
+				log.Printf("live location %s: %v", key, err)
+			}
+		}
+	}
+}
+
+// finishLiveLocation sends the last update of a share that has ended.
+func (m *Manager) finishLiveLocation(share *liveShare, send LiveLocationSender) {
+	m.liveMu.Lock()
+	share.loc.Sequence++
+	loc := share.loc
+	m.liveMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), liveLocationFinalTimeout)
+	defer cancel()
+	if err := send(ctx, loc); err != nil {
+		log.Printf("live location %s: last update: %v", liveKey(loc.Session, loc.ID), err)
+	}
+}
+
+// UpdateLiveLocation changes the position the next re-send will carry.
+func (m *Manager) UpdateLiveLocation(session, id string, lat, lng float64, accuracy uint32) (LiveLocation, bool) {
+	key, err := normalizeSession(session)
+	if err != nil {
+		return LiveLocation{}, false
+	}
+
+	m.liveMu.Lock()
+	defer m.liveMu.Unlock()
+
+	share, ok := m.live[liveKey(key, id)]
+	if !ok {
+		return LiveLocation{}, false
+	}
+	share.loc.Latitude = lat
+	share.loc.Longitude = lng
+	share.loc.AccuracyMeters = accuracy
+	return share.loc, true
+}
+
+func (m *Manager) StopLiveLocation(session, id string) bool {
+	key, err := normalizeSession(session)
+	if err != nil {
+		return false
+	}
+
+	m.liveMu.Lock()
+	defer m.liveMu.Unlock()
+
+	share, ok := m.live[liveKey(key, id)]
+	if !ok {
+		return false
+	}
+	share.cancel(errLiveLocationStopped)
+	delete(m.live, liveKey(key, id))
+	return true
+}
+
+func (m *Manager) stopLiveLocations(session string) {
+	m.liveMu.Lock()
+	defer m.liveMu.Unlock()
+
+	for key, share := range m.live {
+		if share.loc.Session != session {
+			continue
+		}
+		share.cancel(nil)
+		delete(m.live, key)
+	}
+}
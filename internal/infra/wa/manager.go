@@ -29,6 +29,11 @@ type Manager struct {
 	statusMu   sync.Mutex
 	pairMu     sync.RWMutex
 	pairing    map[string]PairingState
+	liveMu     sync.Mutex
+	live       map[string]*liveShare
+	events     eventHub
+	outbox     webhookOutbox
+	hooks      webhookRunner
 }
 
 func NewManager(dbBasePath string, logger walog.Logger) *Manager {
@@ -41,6 +46,9 @@ func NewManager(dbBasePath string, logger walog.Logger) *Manager {
 		status:     make(map[string]string),
 		statusFile: statusFilePath(dbBasePath),
 		pairing:    make(map[string]PairingState),
+		live:       make(map[string]*liveShare),
+		events:     eventHub{logs: make(map[string]*sessionEventLog), subs: make(map[int]chan Event)},
+		hooks:      webhookRunner{wake: make(map[string]chan struct{})},
 	}
 	m.loadPersistedStatuses()
 	return m
@@ -250,11 +258,16 @@ func (m *Manager) SessionStatus(session string, client *whatsmeow.Client) string
 
 func (m *Manager) setStatus(session, status string) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	prev := m.status[session]
 	m.status[session] = status
 	if status == "logout" {
 		_ = m.persistStatus(session, status)
 	}
+	m.mu.Unlock()
+
+	if prev != status {
+		m.publish(Event{Session: session, Type: EventStatus, Data: StatusEvent{Status: status}})
+	}
 }
 
 func (m *Manager) getStatus(session string) (string, bool) {
@@ -266,9 +279,35 @@ func (m *Manager) getStatus(session string) (string, bool) {
 
 func (m *Manager) registerEventHandlers(session string, client *whatsmeow.Client) {
 	client.AddEventHandler(func(evt interface{}) {
-		switch evt.(type) {
+		switch e := evt.(type) {
 		case *events.LoggedOut:
 			m.setStatus(session, "logout")
+			m.publishConnection(session, "logged_out")
+		case *events.Connected:
+			m.publishConnection(session, "connected")
+		case *events.Disconnected:
+			m.publishConnection(session, "disconnected")
+		case *events.PairSuccess:
+			useStatusAudience(client.Store)
+			m.publish(Event{Session: session, Type: EventPairing, Data: PairingEvent{Status: "paired"}})
+		case *events.Receipt:
+			m.handleReceipt(session, e)
+		case *events.Presence:
+			m.handlePresence(session, e)
+		case *events.ChatPresence:
+			m.handleChatPresence(session, e)
+		case *events.GroupInfo:
+			m.trackGroupEphemeral(session, e)
+		case *events.Message:
+			m.trackEphemeral(session, e)
+			switch {
+			case e.Message.GetReactionMessage() != nil:
+				m.handleReaction(session, e)
+			case e.Message.GetPollUpdateMessage() != nil:
+				m.handlePollVote(session, client, e)
+			default:
+				m.handleMessage(session, e)
+			}
 		}
 	})
 }
@@ -0,0 +1,319 @@
+package wa
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// StoredMessage is the gateway's own copy of a message, kept so later
+// requests (quotes, edits, revokes) can refer back to it.
+type StoredMessage struct {
+	ID        string
+	Chat      types.JID
+	Sender    types.JID
+	FromMe    bool
+	Timestamp time.Time
+	Message   *waE2E.Message
+}
+
+const messageSchema = `
+CREATE TABLE IF NOT EXISTS gateway_messages (
+	chat      TEXT    NOT NULL,
+	id        TEXT    NOT NULL,
+	sender    TEXT    NOT NULL,
+	from_me   INTEGER NOT NULL,
+	timestamp INTEGER NOT NULL,
+	message   BLOB    NOT NULL,
+	PRIMARY KEY (chat, id)
+);
+CREATE INDEX IF NOT EXISTS gateway_messages_id ON gateway_messages (id);
+`
+
+const (
+	// Stored messages are kept for messageRetention, and a session keeps at
+	// most messageStoreCapacity of the latest, so the store does not grow
+	// without bound. Reactions and poll votes are kept as long.
+	messageRetention     = 30 * 24 * time.Hour
+	messageStoreCapacity = 50000
+	// messageTrimEvery is how many messages are stored between trims.
+	messageTrimEvery = 500
+)
+
+// upgradeGatewaySchema creates the gateway's tables next to the whatsmeow
+// store tables in a session database.
+func upgradeGatewaySchema(db *sql.DB) error {
+	for _, schema := range []string{messageSchema, reactionSchema, pollSchema, ephemeralSchema, statusPostSchema, chatReadSchema, settingsSchema, eventLogSchema} {
+		if _, err := db.ExecContext(context.Background(), schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sessionDB returns the session's database, opening its store if needed. It
+// never creates a store: a session that has none is ErrSessionNotFound, so a
+// mistyped name cannot leave a new session behind.
+func (m *Manager) sessionDB(session string) (*sql.DB, error) {
+	key, err := normalizeSession(session)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.containers[key]; !ok {
+		if _, err := os.Stat(dbPathForSession(m.dbBasePath, key)); err != nil {
+			if os.IsNotExist(err) {
+				return nil, ErrSessionNotFound
+			}
+			return nil, err
+		}
+	}
+	if _, err := m.getContainerLocked(key); err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	return m.dbs[key], nil
+}
+
+func (m *Manager) SaveMessage(ctx context.Context, session string, msg StoredMessage) error {
+	if msg.ID == "" || msg.Message == nil {
+		return fmt.Errorf("message id and content are required")
+	}
+
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(msg.Message)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	var rowID int64
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO gateway_messages (chat, id, sender, from_me, timestamp, message)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (chat, id) DO UPDATE SET message = excluded.message
+		RETURNING rowid`,
+		msg.Chat.ToNonAD().String(), msg.ID, msg.Sender.ToNonAD().String(), msg.FromMe, msg.Timestamp.Unix(), data,
+	).Scan(&rowID)
+	if err != nil {
+		return err
+	}
+
+	if rowID%messageTrimEvery == 0 {
+		if err := trimMessages(ctx, db, time.Now().Add(-messageRetention)); err != nil {
+			m.log.Warnf("trim message store of %s: %v", session, err)
+		}
+	}
+	return nil
+}
+
+// trimMessages drops what is older than before, then the oldest messages
+// beyond messageStoreCapacity.
+func trimMessages(ctx context.Context, db *sql.DB, before time.Time) error {
+	for _, query := range []string{
+		`DELETE FROM gateway_messages WHERE timestamp < ?`,
+		`DELETE FROM gateway_reactions WHERE timestamp < ?`,
+		`DELETE FROM gateway_poll_votes WHERE timestamp < ?`,
+	} {
+		if _, err := db.ExecContext(ctx, query, before.Unix()); err != nil {
+			return err
+		}
+	}
+
+	_, err := db.ExecContext(ctx, `
+		DELETE FROM gateway_messages WHERE rowid IN (
+			SELECT rowid FROM gateway_messages ORDER BY timestamp DESC LIMIT -1 OFFSET ?)`,
+		messageStoreCapacity,
+	)
+	return err
+}
+
+// DeleteMessage forgets a revoked message, along with its reactions and poll
+// votes.
+func (m *Manager) DeleteMessage(ctx context.Context, session string, chat types.JID, id string) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, query := range []string{
+		`DELETE FROM gateway_messages WHERE chat = ? AND id = ?`,
+		`DELETE FROM gateway_reactions WHERE chat = ? AND message_id = ?`,
+		`DELETE FROM gateway_poll_votes WHERE chat = ? AND poll_id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, chat.ToNonAD().String(), id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// replaceMessageContent gives a stored message the content it was edited to.
+// Messages that are not stored are left alone.
+func (m *Manager) replaceMessageContent(ctx context.Context, session string, chat types.JID, id string, msg *waE2E.Message) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		UPDATE gateway_messages SET message = ? WHERE chat = ? AND id = ?`,
+		data, chat.ToNonAD().String(), id,
+	)
+	return err
+}
+
+// GetMessage looks a message up by ID, preferring a copy stored under chat
+// since the same chat can be addressed by phone number or by LID.
+func (m *Manager) GetMessage(ctx context.Context, session string, chat types.JID, id string) (*StoredMessage, bool, error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		chatRaw, senderRaw string
+		fromMe             bool
+		ts                 int64
+		data               []byte
+	)
+	err = db.QueryRowContext(ctx, `
+		SELECT chat, sender, from_me, timestamp, message FROM gateway_messages
+		WHERE id = ? ORDER BY chat = ? DESC LIMIT 1`,
+		id, chat.ToNonAD().String(),
+	).Scan(&chatRaw, &senderRaw, &fromMe, &ts, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	out := &StoredMessage{ID: id, FromMe: fromMe, Timestamp: time.Unix(ts, 0), Message: &waE2E.Message{}}
+	if out.Chat, err = types.ParseJID(chatRaw); err != nil {
+		return nil, false, err
+	}
+	if out.Sender, err = types.ParseJID(senderRaw); err != nil {
+		return nil, false, err
+	}
+	if err := proto.Unmarshal(data, out.Message); err != nil {
+		return nil, false, fmt.Errorf("unmarshal message: %w", err)
+	}
+	return out, true, nil
+}
+
+// HasChatMessages reports whether any message of chat is stored.
+func (m *Manager) HasChatMessages(ctx context.Context, session string, chat types.JID) (bool, error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return false, err
+	}
+
+	var found bool
+	err = db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM gateway_messages WHERE chat = ?)`,
+		chat.ToNonAD().String(),
+	).Scan(&found)
+	return found, err
+}
+
+// storeIncoming keeps a copy of messages with user-visible content. Protocol
+// messages, reactions and the like only make sense relative to another
+// message and are not worth quoting; revokes and edits update the copy they
+// refer to. View-once media is meant to be seen once and is not kept.
+func (m *Manager) storeIncoming(session string, evt *events.Message) {
+	msg := evt.Message
+	if pm := msg.GetProtocolMessage(); pm != nil {
+		m.followProtocolMessage(session, evt, pm)
+		return
+	}
+	if msg == nil || msg.GetReactionMessage() != nil || msg.GetPollUpdateMessage() != nil ||
+		onlySenderKey(msg) || evt.IsViewOnce {
+		return
+	}
+
+	err := m.SaveMessage(context.Background(), session, StoredMessage{
+		ID:        evt.Info.ID,
+		Chat:      canonicalChat(evt.Info.MessageSource),
+		Sender:    evt.Info.Sender,
+		FromMe:    evt.Info.IsFromMe,
+		Timestamp: evt.Info.Timestamp,
+		Message:   msg,
+	})
+	if err != nil {
+		m.log.Warnf("store message %s in %s: %v", evt.Info.ID, session, err)
+	}
+}
+
+// followProtocolMessage deletes the stored copy of a revoked message and
+// gives the copy of an edited one its new content.
+func (m *Manager) followProtocolMessage(session string, evt *events.Message, pm *waE2E.ProtocolMessage) {
+	var (
+		ctx  = context.Background()
+		chat = canonicalChat(evt.Info.MessageSource)
+		id   = pm.GetKey().GetID()
+		err  error
+	)
+	switch {
+	case id == "":
+		return
+	case pm.GetType() == waE2E.ProtocolMessage_REVOKE:
+		err = m.DeleteMessage(ctx, session, chat, id)
+	case pm.GetType() == waE2E.ProtocolMessage_MESSAGE_EDIT && pm.GetEditedMessage() != nil:
+		err = m.replaceMessageContent(ctx, session, chat, id, pm.GetEditedMessage())
+	default:
+		return
+	}
+	if err != nil {
+		m.log.Warnf("update stored message %s in %s: %v", id, session, err)
+	}
+}
+
+func onlySenderKey(msg *waE2E.Message) bool {
+	if msg.GetSenderKeyDistributionMessage() == nil {
+		return false
+	}
+	clone := proto.Clone(msg).(*waE2E.Message)
+	clone.SenderKeyDistributionMessage = nil
+	clone.MessageContextInfo = nil
+	return proto.Size(clone) == 0
+}
+
+// canonicalChat prefers the phone-number JID of a direct chat over its LID
+// so messages are stored under the JID callers send to.
+func canonicalChat(src types.MessageSource) types.JID {
+	chat := src.Chat
+	if src.IsGroup || chat.Server != types.HiddenUserServer {
+		return chat
+	}
+	if !src.IsFromMe && src.SenderAlt.Server == types.DefaultUserServer {
+		return src.SenderAlt.ToNonAD()
+	}
+	if src.IsFromMe && src.RecipientAlt.Server == types.DefaultUserServer {
+		return src.RecipientAlt.ToNonAD()
+	}
+	return chat
+}
@@ -0,0 +1,141 @@
+package wa
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	walog "go.mau.fi/whatsmeow/util/log"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSessionDBDoesNotCreateStores(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	m := NewManager(dir, walog.Noop)
+
+	if _, err := m.Settings(ctx, "typo"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Settings of an unknown session: %v, want ErrSessionNotFound", err)
+	}
+	if err := m.SaveSettings(ctx, "typo", SessionSettings{AutoRead: true}); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("SaveSettings of an unknown session: %v, want ErrSessionNotFound", err)
+	}
+	if _, err := os.Stat(dbPathForSession(dir, "typo")); !os.IsNotExist(err) {
+		t.Fatalf("store of the unknown session was created: %v", err)
+	}
+	if exists, err := m.SessionExists("typo"); err != nil || exists {
+		t.Fatalf("SessionExists = %v, %v; want false", exists, err)
+	}
+
+	// A store on disk is opened even when the session is not loaded yet.
+	if _, err := m.getContainer("s1"); err != nil {
+		t.Fatal(err)
+	}
+	reopened := NewManager(dir, walog.Noop)
+	if _, err := reopened.Settings(ctx, "s1"); err != nil {
+		t.Fatalf("Settings of a stored session: %v", err)
+	}
+}
+
+func TestStoreIncoming(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(t.TempDir(), walog.Noop)
+	if _, err := m.getContainer("s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	chat := types.NewJID("6281200000001", types.DefaultUserServer)
+	message := func(id string, msg *waE2E.Message) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+				ID:            id,
+				Timestamp:     time.Now(),
+			},
+			Message: msg,
+		}
+	}
+	protocol := func(id string, pm *waE2E.ProtocolMessage) *events.Message {
+		pm.Key = &waCommon.MessageKey{ID: proto.String(id)}
+		return message("PROTO-"+id, &waE2E.Message{ProtocolMessage: pm})
+	}
+	text := func(s string) *waE2E.Message { return &waE2E.Message{Conversation: proto.String(s)} }
+
+	m.storeIncoming("s1", message("EDITED", text("first")))
+	m.storeIncoming("s1", message("REVOKED", text("oops")))
+	viewOnce := message("VIEW-ONCE", text("secret"))
+	viewOnce.IsViewOnce = true
+	m.storeIncoming("s1", viewOnce)
+
+	m.storeIncoming("s1", protocol("EDITED", &waE2E.ProtocolMessage{
+		Type:          waE2E.ProtocolMessage_MESSAGE_EDIT.Enum(),
+		EditedMessage: text("second"),
+	}))
+	m.storeIncoming("s1", protocol("REVOKED", &waE2E.ProtocolMessage{Type: waE2E.ProtocolMessage_REVOKE.Enum()}))
+
+	tests := []struct {
+		id        string
+		wantFound bool
+		wantText  string
+	}{
+		{"EDITED", true, "second"},
+		{"REVOKED", false, ""},
+		{"VIEW-ONCE", false, ""},
+		{"PROTO-EDITED", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			stored, found, err := m.GetMessage(ctx, "s1", chat, tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("found = %v, want %v", found, tt.wantFound)
+			}
+			if found && stored.Message.GetConversation() != tt.wantText {
+				t.Errorf("text = %q, want %q", stored.Message.GetConversation(), tt.wantText)
+			}
+		})
+	}
+}
+
+func TestTrimMessages(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(t.TempDir(), walog.Noop)
+	if _, err := m.getContainer("s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	chat := types.NewJID("6281200000001", types.DefaultUserServer)
+	now := time.Now()
+	for _, msg := range []StoredMessage{
+		{ID: "OLD", Timestamp: now.Add(-2 * messageRetention)},
+		{ID: "NEW", Timestamp: now},
+	} {
+		msg.Chat, msg.Sender = chat, chat
+		msg.Message = &waE2E.Message{Conversation: proto.String(msg.ID)}
+		if err := m.SaveMessage(ctx, "s1", msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := m.sessionDB("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trimMessages(ctx, db, now.Add(-messageRetention)); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]bool{"OLD": false, "NEW": true} {
+		if _, found, err := m.GetMessage(ctx, "s1", chat, id); err != nil || found != want {
+			t.Errorf("GetMessage(%s) found = %v, %v; want %v", id, found, err, want)
+		}
+	}
+}
@@ -54,6 +54,12 @@ func (m *Manager) UpdatePairingCode(session, code string, issuedAt time.Time, tt
 	state.LastError = ""
 	state.NextRetryAt = time.Time{}
 	m.pairing[key] = state
+
+	m.publish(Event{Session: key, Type: EventPairing, Data: PairingEvent{
+		Status:    "code",
+		Code:      code,
+		ExpiresAt: state.ExpiresAt.Unix(),
+	}})
 	return nil
 }
 
@@ -75,6 +81,12 @@ func (m *Manager) UpdatePairingFailure(session, errMsg string, at time.Time, bac
 		state.NextRetryAt = time.Time{}
 	}
 	m.pairing[key] = state
+
+	evt := PairingEvent{Status: "failed", Error: errMsg}
+	if backoff > 0 {
+		evt.RetryAt = state.NextRetryAt.Unix()
+	}
+	m.publish(Event{Session: key, Type: EventPairing, Data: evt})
 	return nil
 }
 
@@ -0,0 +1,179 @@
+package wa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const EventPollVote = "poll_vote"
+
+type PollVoteEvent struct {
+	Chat     string   `json:"chat"`
+	PollID   string   `json:"poll_id"`
+	Voter    string   `json:"voter"`
+	Selected []string `json:"selected"`
+}
+
+// PollVote is the latest vote of one voter; Selected is empty once the voter
+// has withdrawn.
+type PollVote struct {
+	Voter     types.JID
+	Selected  []string
+	Timestamp time.Time
+}
+
+const pollSchema = `
+CREATE TABLE IF NOT EXISTS gateway_poll_votes (
+	chat      TEXT    NOT NULL,
+	poll_id   TEXT    NOT NULL,
+	voter     TEXT    NOT NULL,
+	selected  TEXT    NOT NULL,
+	timestamp INTEGER NOT NULL,
+	PRIMARY KEY (chat, poll_id, voter)
+);
+`
+
+// PollCreationOf returns the poll in msg, whichever of the poll creation
+// message versions carries it.
+func PollCreationOf(msg *waE2E.Message) *waE2E.PollCreationMessage {
+	switch {
+	case msg.GetPollCreationMessage() != nil:
+		return msg.GetPollCreationMessage()
+	case msg.GetPollCreationMessageV2() != nil:
+		return msg.GetPollCreationMessageV2()
+	case msg.GetPollCreationMessageV3() != nil:
+		return msg.GetPollCreationMessageV3()
+	}
+	return nil
+}
+
+func (m *Manager) ListPollVotes(ctx context.Context, session string, chat types.JID, pollID string) ([]PollVote, error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT voter, selected, timestamp FROM gateway_poll_votes
+		WHERE chat = ? AND poll_id = ? ORDER BY timestamp`,
+		chat.ToNonAD().String(), pollID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]PollVote, 0)
+	for rows.Next() {
+		var (
+			voterRaw, selectedRaw string
+			ts                    int64
+			vote                  PollVote
+		)
+		if err := rows.Scan(&voterRaw, &selectedRaw, &ts); err != nil {
+			return nil, err
+		}
+		if vote.Voter, err = types.ParseJID(voterRaw); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(selectedRaw), &vote.Selected); err != nil {
+			return nil, err
+		}
+		vote.Timestamp = time.Unix(ts, 0)
+		out = append(out, vote)
+	}
+	return out, rows.Err()
+}
+
+func (m *Manager) savePollVote(ctx context.Context, session string, chat types.JID, pollID string, vote PollVote) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	selected, err := json.Marshal(vote.Selected)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO gateway_poll_votes (chat, poll_id, voter, selected, timestamp)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (chat, poll_id, voter) DO UPDATE SET selected = excluded.selected, timestamp = excluded.timestamp
+		WHERE excluded.timestamp >= gateway_poll_votes.timestamp`,
+		chat.ToNonAD().String(), pollID, vote.Voter.ToNonAD().String(), string(selected), vote.Timestamp.Unix(),
+	)
+	return err
+}
+
+// handlePollVote decrypts a vote with the poll's secret and records it. Votes
+// only carry hashes of the chosen options, so they are matched against the
+// stored poll to recover the option names.
+func (m *Manager) handlePollVote(session string, client *whatsmeow.Client, evt *events.Message) {
+	ctx := context.Background()
+	pollID := evt.Message.GetPollUpdateMessage().GetPollCreationMessageKey().GetID()
+	chat := canonicalChat(evt.Info.MessageSource)
+
+	poll, found, err := m.GetMessage(ctx, session, chat, pollID)
+	if err != nil || !found || PollCreationOf(poll.Message) == nil {
+		m.log.Warnf("poll vote %s in %s: poll %s not stored", evt.Info.ID, session, pollID)
+		return
+	}
+
+	vote, err := client.DecryptPollVote(ctx, evt)
+	if errors.Is(err, whatsmeow.ErrOriginalMessageSecretNotFound) {
+		// whatsmeow lost the secret; restore it from the gateway's copy.
+		if secret := poll.Message.GetMessageContextInfo().GetMessageSecret(); len(secret) > 0 {
+			if putErr := client.Store.MsgSecrets.PutMessageSecret(ctx, evt.Info.Chat, poll.Sender, pollID, secret); putErr == nil {
+				vote, err = client.DecryptPollVote(ctx, evt)
+			}
+		}
+	}
+	if err != nil {
+		m.log.Warnf("poll vote %s in %s: %v", evt.Info.ID, session, err)
+		return
+	}
+
+	names := make(map[[sha256.Size]byte]string)
+	for _, option := range PollCreationOf(poll.Message).GetOptions() {
+		names[sha256.Sum256([]byte(option.GetOptionName()))] = option.GetOptionName()
+	}
+	selected := make([]string, 0, len(vote.GetSelectedOptions()))
+	for _, hash := range vote.GetSelectedOptions() {
+		if len(hash) != sha256.Size {
+			continue
+		}
+		if name, ok := names[[sha256.Size]byte(hash)]; ok {
+			selected = append(selected, name)
+		}
+	}
+
+	err = m.savePollVote(ctx, session, chat, pollID, PollVote{
+		Voter:     evt.Info.Sender,
+		Selected:  selected,
+		Timestamp: evt.Info.Timestamp,
+	})
+	if err != nil {
+		m.log.Warnf("store poll vote %s in %s: %v", evt.Info.ID, session, err)
+	}
+
+	m.publish(Event{
+		Session:   session,
+		Type:      EventPollVote,
+		Timestamp: evt.Info.Timestamp,
+		Data: PollVoteEvent{
+			Chat:     chat.String(),
+			PollID:   pollID,
+			Voter:    evt.Info.Sender.ToNonAD().String(),
+			Selected: selected,
+		},
+	})
+}
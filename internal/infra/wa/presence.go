@@ -0,0 +1,66 @@
+package wa
+
+import (
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// handleReceipt announces delivery, read and play receipts for messages the
+// session sent. Receipts about the session's own reads on other devices and
+// protocol-level receipts are left out.
+func (m *Manager) handleReceipt(session string, evt *events.Receipt) {
+	var typ string
+	switch evt.Type {
+	case types.ReceiptTypeDelivered:
+		typ = "delivered"
+	case types.ReceiptTypeRead:
+		typ = "read"
+	case types.ReceiptTypePlayed:
+		typ = "played"
+	default:
+		return
+	}
+
+	m.publish(Event{
+		Session:   session,
+		Type:      EventReceipt,
+		Timestamp: evt.Timestamp,
+		Data: ReceiptEvent{
+			Chat:       canonicalChat(evt.MessageSource).String(),
+			Sender:     evt.Sender.ToNonAD().String(),
+			IsGroup:    evt.IsGroup,
+			MessageIDs: evt.MessageIDs,
+			Type:       typ,
+		},
+	})
+}
+
+func (m *Manager) handlePresence(session string, evt *events.Presence) {
+	data := PresenceEvent{
+		JID:       evt.From.ToNonAD().String(),
+		Available: !evt.Unavailable,
+	}
+	if !evt.LastSeen.IsZero() {
+		data.LastSeen = evt.LastSeen.Unix()
+	}
+	m.publish(Event{Session: session, Type: EventPresence, Data: data})
+}
+
+func (m *Manager) handleChatPresence(session string, evt *events.ChatPresence) {
+	m.publish(Event{
+		Session: session,
+		Type:    EventChatPresence,
+		Data: ChatPresenceEvent{
+			Chat:   canonicalChat(evt.MessageSource).String(),
+			Sender: evt.Sender.ToNonAD().String(),
+			State:  string(evt.State),
+			Media:  string(evt.Media),
+		},
+	})
+}
+
+// publishConnection announces a change in the session's connection: status
+// is connected, disconnected or logged_out.
+func (m *Manager) publishConnection(session, status string) {
+	m.publish(Event{Session: session, Type: EventConnection, Data: ConnectionEvent{Status: status}})
+}
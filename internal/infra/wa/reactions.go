@@ -0,0 +1,124 @@
+package wa
+
+import (
+	"context"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Reaction is the current reaction of one sender to a message.
+type Reaction struct {
+	Chat      types.JID
+	MessageID string
+	Sender    types.JID
+	Emoji     string
+	Timestamp time.Time
+}
+
+const reactionSchema = `
+CREATE TABLE IF NOT EXISTS gateway_reactions (
+	chat       TEXT    NOT NULL,
+	message_id TEXT    NOT NULL,
+	sender     TEXT    NOT NULL,
+	emoji      TEXT    NOT NULL,
+	timestamp  INTEGER NOT NULL,
+	PRIMARY KEY (chat, message_id, sender)
+);
+`
+
+// SaveReaction records r, replacing the sender's earlier reaction to the same
+// message. An empty emoji removes it.
+func (m *Manager) SaveReaction(ctx context.Context, session string, r Reaction) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	chat, sender := r.Chat.ToNonAD().String(), r.Sender.ToNonAD().String()
+	if r.Emoji == "" {
+		_, err = db.ExecContext(ctx, `
+			DELETE FROM gateway_reactions WHERE chat = ? AND message_id = ? AND sender = ?`,
+			chat, r.MessageID, sender,
+		)
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO gateway_reactions (chat, message_id, sender, emoji, timestamp)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (chat, message_id, sender) DO UPDATE SET emoji = excluded.emoji, timestamp = excluded.timestamp`,
+		chat, r.MessageID, sender, r.Emoji, r.Timestamp.Unix(),
+	)
+	return err
+}
+
+func (m *Manager) ListReactions(ctx context.Context, session string, chat types.JID, messageID string) ([]Reaction, error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT sender, emoji, timestamp FROM gateway_reactions
+		WHERE chat = ? AND message_id = ? ORDER BY timestamp`,
+		chat.ToNonAD().String(), messageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Reaction, 0)
+	for rows.Next() {
+		var (
+			senderRaw string
+			ts        int64
+		)
+		r := Reaction{Chat: chat, MessageID: messageID}
+		if err := rows.Scan(&senderRaw, &r.Emoji, &ts); err != nil {
+			return nil, err
+		}
+		if r.Sender, err = types.ParseJID(senderRaw); err != nil {
+			return nil, err
+		}
+		r.Timestamp = time.Unix(ts, 0)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (m *Manager) handleReaction(session string, evt *events.Message) {
+	reaction := evt.Message.GetReactionMessage()
+	key := reaction.GetKey()
+	if key.GetID() == "" {
+		return
+	}
+
+	chat := canonicalChat(evt.Info.MessageSource)
+	r := Reaction{
+		Chat:      chat,
+		MessageID: key.GetID(),
+		Sender:    evt.Info.Sender,
+		Emoji:     reaction.GetText(),
+		Timestamp: evt.Info.Timestamp,
+	}
+	if err := m.SaveReaction(context.Background(), session, r); err != nil {
+		m.log.Warnf("store reaction %s in %s: %v", evt.Info.ID, session, err)
+	}
+
+	m.publish(Event{
+		Session:   session,
+		Type:      EventReaction,
+		Timestamp: evt.Info.Timestamp,
+		Data: ReactionEvent{
+			Chat:      chat.String(),
+			Sender:    evt.Info.Sender.ToNonAD().String(),
+			FromMe:    evt.Info.IsFromMe,
+			MessageID: r.MessageID,
+			Emoji:     r.Emoji,
+			Removed:   r.Emoji == "",
+		},
+	})
+}
@@ -0,0 +1,136 @@
+package wa
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// ReadTarget is a message a read receipt is sent for.
+type ReadTarget struct {
+	ID        string
+	Sender    types.JID
+	Timestamp time.Time
+}
+
+// The gateway remembers up to when each chat has been read, so marking a
+// whole chat read only sends receipts for messages that arrived since.
+const chatReadSchema = `
+CREATE TABLE IF NOT EXISTS gateway_chat_reads (
+	chat       TEXT    PRIMARY KEY,
+	read_until INTEGER NOT NULL
+);
+`
+
+// UnreadMessages lists the stored incoming messages of chat newer than the
+// last time the chat was marked read, oldest first.
+func (m *Manager) UnreadMessages(ctx context.Context, session string, chat types.JID) ([]ReadTarget, error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, sender, timestamp FROM gateway_messages
+		WHERE chat = ? AND from_me = 0 AND timestamp > COALESCE(
+			(SELECT read_until FROM gateway_chat_reads WHERE chat = ?), 0)
+		ORDER BY timestamp`,
+		chat.ToNonAD().String(), chat.ToNonAD().String(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ReadTarget
+	for rows.Next() {
+		var (
+			t         ReadTarget
+			senderRaw string
+			ts        int64
+		)
+		if err := rows.Scan(&t.ID, &senderRaw, &ts); err != nil {
+			return nil, err
+		}
+		if t.Sender, err = types.ParseJID(senderRaw); err != nil {
+			return nil, err
+		}
+		t.Timestamp = time.Unix(ts, 0)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// SaveChatRead records that chat has been read up to until. The mark never
+// moves backwards.
+func (m *Manager) SaveChatRead(ctx context.Context, session string, chat types.JID, until time.Time) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO gateway_chat_reads (chat, read_until) VALUES (?, ?)
+		ON CONFLICT (chat) DO UPDATE SET read_until = MAX(read_until, excluded.read_until)`,
+		chat.ToNonAD().String(), until.Unix(),
+	)
+	return err
+}
+
+// SendReadReceipts marks targets in chat read. WhatsApp takes one receipt per
+// sender, so targets are grouped by who sent them.
+func SendReadReceipts(ctx context.Context, client *whatsmeow.Client, chat types.JID, targets []ReadTarget) error {
+	var (
+		senders []types.JID
+		ids     = make(map[types.JID][]types.MessageID)
+	)
+	for _, t := range targets {
+		sender := t.Sender.ToNonAD()
+		if _, ok := ids[sender]; !ok {
+			senders = append(senders, sender)
+		}
+		ids[sender] = append(ids[sender], t.ID)
+	}
+
+	now := time.Now()
+	for _, sender := range senders {
+		if err := client.MarkRead(ctx, ids[sender], now, chat, sender); err != nil {
+			return fmt.Errorf("mark read: %w", err)
+		}
+	}
+	return nil
+}
+
+// markAcknowledgedRead sends a read receipt for a message that has been
+// taken in, if the session has auto-read turned on, and moves the chat's
+// read mark up to it so marking the whole chat read later does not send the
+// receipt again. Statuses are left unseen.
+func (m *Manager) markAcknowledgedRead(ctx context.Context, session string, chat types.JID, target ReadTarget) {
+	if chat.Server == types.BroadcastServer {
+		return
+	}
+
+	settings, err := m.Settings(ctx, session)
+	if err != nil {
+		m.log.Warnf("load settings of %s: %v", session, err)
+		return
+	}
+	if !settings.AutoRead {
+		return
+	}
+
+	client, ok := m.GetClient(session)
+	if !ok || !client.IsConnected() {
+		return
+	}
+	if err := SendReadReceipts(ctx, client, chat, []ReadTarget{target}); err != nil {
+		m.log.Warnf("auto-read %s in %s: %v", target.ID, session, err)
+		return
+	}
+	if err := m.SaveChatRead(ctx, session, chat, target.Timestamp); err != nil {
+		m.log.Warnf("store read mark of %s in %s: %v", chat, session, err)
+	}
+}
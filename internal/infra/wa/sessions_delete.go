@@ -2,6 +2,7 @@ package wa
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -88,6 +89,7 @@ func (m *Manager) deleteSessionWithRetry(ctx context.Context, session string, at
 	m.pairMu.Unlock()
 
 	_ = m.clearPersistedStatus(key)
+	m.stopLiveLocations(key)
 
 	if client != nil {
 		disconnectClient(client)
@@ -143,6 +145,19 @@ func removeFileWithRetry(path string, attempts int, delay time.Duration) (bool,
 	return false, lastErr
 }
 
+// ErrSessionNotFound is returned for a session the gateway has no store for.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionExists tells whether the gateway knows session, in memory or on
+// disk.
+func (m *Manager) SessionExists(session string) (bool, error) {
+	key, err := normalizeSession(session)
+	if err != nil {
+		return false, err
+	}
+	return m.sessionExists(key)
+}
+
 func (m *Manager) sessionExists(session string) (bool, error) {
 	m.mu.RLock()
 	if _, ok := m.clients[session]; ok {
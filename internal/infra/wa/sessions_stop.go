@@ -33,6 +33,8 @@ func (m *Manager) StopSession(ctx context.Context, session string) (bool, error)
 	}
 	m.mu.Unlock()
 
+	m.stopLiveLocations(key)
+
 	if client != nil {
 		disconnectClient(client)
 	}
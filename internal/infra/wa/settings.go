@@ -0,0 +1,102 @@
+package wa
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// SessionSettings are the gateway options a session can be configured with.
+type SessionSettings struct {
+	// AutoRead marks incoming messages read once a webhook consumer has
+	// acknowledged them.
+	AutoRead bool `json:"auto_read"`
+	// WebhookURL receives the session's events; empty turns webhooks off.
+	WebhookURL string `json:"webhook_url"`
+	// WebhookEvents limits the webhook to these event types; empty sends all.
+	WebhookEvents []string `json:"webhook_events"`
+	// WebhookSecret signs webhook deliveries; empty leaves them unsigned.
+	WebhookSecret string `json:"webhook_secret"`
+	// WebhookPreviousSecret is the secret being rotated out. Deliveries are
+	// signed with it too until it is retired.
+	WebhookPreviousSecret string `json:"webhook_previous_secret"`
+}
+
+// webhookSecrets lists the secrets deliveries are signed with, current first.
+func (s SessionSettings) webhookSecrets() []string {
+	var out []string
+	for _, secret := range []string{s.WebhookSecret, s.WebhookPreviousSecret} {
+		if secret != "" {
+			out = append(out, secret)
+		}
+	}
+	return out
+}
+
+func (s SessionSettings) wantsEvent(typ string) bool {
+	if len(s.WebhookEvents) == 0 {
+		return true
+	}
+	return slices.Contains(s.WebhookEvents, typ)
+}
+
+// Settings are kept as a single JSON row so new options need no migration.
+const settingsSchema = `
+CREATE TABLE IF NOT EXISTS gateway_settings (
+	id       INTEGER PRIMARY KEY CHECK (id = 1),
+	settings TEXT    NOT NULL
+);
+`
+
+// Settings returns the session's settings, or the defaults if none were
+// saved yet.
+func (m *Manager) Settings(ctx context.Context, session string) (SessionSettings, error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return SessionSettings{}, err
+	}
+
+	var (
+		raw      string
+		settings SessionSettings
+	)
+	err = db.QueryRowContext(ctx, `SELECT settings FROM gateway_settings WHERE id = 1`).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return settings, nil
+	}
+	if err != nil {
+		return SessionSettings{}, err
+	}
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		return SessionSettings{}, fmt.Errorf("decode settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings stores the session's settings and wakes its webhook worker, so
+// deliveries held back while it had no webhook URL go out.
+func (m *Manager) SaveSettings(ctx context.Context, session string, settings SessionSettings) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO gateway_settings (id, settings) VALUES (1, ?)
+		ON CONFLICT (id) DO UPDATE SET settings = excluded.settings`,
+		string(raw),
+	)
+	if err != nil {
+		return err
+	}
+	m.wakeWebhooks(session)
+	return nil
+}
@@ -0,0 +1,177 @@
+package wa
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// statusAudienceContacts is installed as every device's contact store.
+// whatsmeow picks status recipients from GetAllContacts, which nothing else
+// in it calls, so while SendStatus holds the device's status lock the store
+// answers with the post's audience instead of the contacts. SendMessage has
+// no way to take the recipients directly, so the tests pin the whatsmeow
+// release this was checked against.
+type statusAudienceContacts struct {
+	store.ContactStore
+
+	// sending serializes the device's status posts, so each is sent with
+	// its own audience.
+	sending  sync.Mutex
+	mu       sync.Mutex
+	audience []types.JID
+}
+
+// useStatusAudience wraps the device's contact store once it has one, which
+// for a new device only happens when it is paired.
+func useStatusAudience(device *store.Device) {
+	if device.Contacts == nil {
+		return
+	}
+	if _, ok := device.Contacts.(*statusAudienceContacts); !ok {
+		device.Contacts = &statusAudienceContacts{ContactStore: device.Contacts}
+	}
+}
+
+func (s *statusAudienceContacts) setAudience(audience []types.JID) {
+	s.mu.Lock()
+	s.audience = audience
+	s.mu.Unlock()
+}
+
+func (s *statusAudienceContacts) GetAllContacts(ctx context.Context) (map[types.JID]types.ContactInfo, error) {
+	s.mu.Lock()
+	audience := s.audience
+	s.mu.Unlock()
+	if len(audience) == 0 {
+		return s.ContactStore.GetAllContacts(ctx)
+	}
+
+	out := make(map[types.JID]types.ContactInfo, len(audience))
+	for _, jid := range audience {
+		info, err := s.ContactStore.GetContact(ctx, jid)
+		if err != nil {
+			return nil, err
+		}
+		// Entries without a name are taken for push names only and skipped.
+		if info.FullName == "" {
+			info.FullName = jid.User
+		}
+		info.Found = true
+		out[jid.ToNonAD()] = info
+	}
+	return out, nil
+}
+
+// SendStatus sends msg to status@broadcast. A non-empty audience is who it
+// goes to instead of the session's contacts; WhatsApp's status privacy still
+// applies, and with "only share with" the account's own list wins.
+func SendStatus(ctx context.Context, client *whatsmeow.Client, msg *waE2E.Message, audience []types.JID, extra whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	contacts, ok := client.Store.Contacts.(*statusAudienceContacts)
+	if !ok {
+		if len(audience) > 0 {
+			return whatsmeow.SendResponse{}, errors.New("session cannot post to a chosen audience yet")
+		}
+		return client.SendMessage(ctx, types.StatusBroadcastJID, msg, extra)
+	}
+
+	contacts.sending.Lock()
+	defer contacts.sending.Unlock()
+	contacts.setAudience(audience)
+	defer contacts.setAudience(nil)
+
+	return client.SendMessage(ctx, types.StatusBroadcastJID, msg, extra)
+}
+
+// StatusPost is a status the session posted, with the audience it went to so
+// that deleting it reaches the same people. An empty Audience means the
+// session's contacts.
+type StatusPost struct {
+	ID        string
+	Audience  []types.JID
+	Timestamp time.Time
+}
+
+const statusPostSchema = `
+CREATE TABLE IF NOT EXISTS gateway_status_posts (
+	id        TEXT    PRIMARY KEY,
+	audience  TEXT    NOT NULL,
+	timestamp INTEGER NOT NULL
+);
+`
+
+func (m *Manager) SaveStatusPost(ctx context.Context, session string, post StatusPost) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	audience := make([]string, 0, len(post.Audience))
+	for _, jid := range post.Audience {
+		audience = append(audience, jid.ToNonAD().String())
+	}
+	raw, err := json.Marshal(audience)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO gateway_status_posts (id, audience, timestamp) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET audience = excluded.audience, timestamp = excluded.timestamp`,
+		post.ID, string(raw), post.Timestamp.Unix(),
+	)
+	return err
+}
+
+func (m *Manager) GetStatusPost(ctx context.Context, session, id string) (*StatusPost, bool, error) {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		raw string
+		ts  int64
+	)
+	err = db.QueryRowContext(ctx, `
+		SELECT audience, timestamp FROM gateway_status_posts WHERE id = ?`, id,
+	).Scan(&raw, &ts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var audience []string
+	if err := json.Unmarshal([]byte(raw), &audience); err != nil {
+		return nil, false, err
+	}
+	post := &StatusPost{ID: id, Timestamp: time.Unix(ts, 0)}
+	for _, s := range audience {
+		jid, err := types.ParseJID(s)
+		if err != nil {
+			return nil, false, err
+		}
+		post.Audience = append(post.Audience, jid)
+	}
+	return post, true, nil
+}
+
+func (m *Manager) DeleteStatusPost(ctx context.Context, session, id string) error {
+	db, err := m.sessionDB(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `DELETE FROM gateway_status_posts WHERE id = ?`, id)
+	return err
+}
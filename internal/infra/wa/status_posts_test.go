@@ -0,0 +1,105 @@
+package wa
+
+import (
+	"context"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// statusAudienceWhatsmeow is the whatsmeow release whose status sending was
+// checked to take its recipients from Contacts.GetAllContacts, keeping those
+// with a FullName (getStatusBroadcastRecipients in broadcast.go). Recheck it
+// before moving the pin along with an upgrade.
+const statusAudienceWhatsmeow = "v0.0.0-20251217143725-11cf47c62d32"
+
+func TestStatusAudienceWhatsmeowVersion(t *testing.T) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Skip("no build info")
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != "go.mau.fi/whatsmeow" {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if dep.Version != statusAudienceWhatsmeow {
+			t.Fatalf("whatsmeow is %s, status audiences were checked against %s", dep.Version, statusAudienceWhatsmeow)
+		}
+		return
+	}
+	t.Fatal("whatsmeow is not a dependency")
+}
+
+type fakeContacts struct {
+	store.ContactStore
+	contacts map[types.JID]types.ContactInfo
+}
+
+func (f *fakeContacts) GetContact(_ context.Context, jid types.JID) (types.ContactInfo, error) {
+	return f.contacts[jid], nil
+}
+
+func (f *fakeContacts) GetAllContacts(context.Context) (map[types.JID]types.ContactInfo, error) {
+	return f.contacts, nil
+}
+
+// statusRecipients picks recipients the way whatsmeow does for a status when
+// the account shares it with its contacts.
+func statusRecipients(t *testing.T, contacts store.ContactStore) []string {
+	t.Helper()
+	all, err := contacts.GetAllContacts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for jid, info := range all {
+		if len(info.FullName) > 0 {
+			out = append(out, jid.String())
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestStatusAudienceContacts(t *testing.T) {
+	var (
+		named    = types.NewJID("6281200000001", types.DefaultUserServer)
+		pushName = types.NewJID("6281200000002", types.DefaultUserServer)
+		stranger = types.NewJID("6281200000003", types.DefaultUserServer)
+	)
+	device := &store.Device{Contacts: &fakeContacts{contacts: map[types.JID]types.ContactInfo{
+		named:    {Found: true, FullName: "Named"},
+		pushName: {Found: true, PushName: "Push"},
+	}}}
+	useStatusAudience(device)
+	contacts, ok := device.Contacts.(*statusAudienceContacts)
+	if !ok {
+		t.Fatalf("contact store is %T, want *statusAudienceContacts", device.Contacts)
+	}
+
+	tests := []struct {
+		name     string
+		audience []types.JID
+		want     []string
+	}{
+		{"contacts", nil, []string{named.String()}},
+		{"chosen audience", []types.JID{pushName, types.NewADJID(stranger.User, 0, 3)}, []string{pushName.String(), stranger.String()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts.setAudience(tt.audience)
+			defer contacts.setAudience(nil)
+
+			if got := statusRecipients(t, contacts); !slices.Equal(got, tt.want) {
+				t.Errorf("recipients = %s, want %s", strings.Join(got, ", "), strings.Join(tt.want, ", "))
+			}
+		})
+	}
+}
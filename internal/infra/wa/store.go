@@ -2,6 +2,7 @@ package wa
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -25,6 +26,10 @@ func (m *Manager) getContainerLocked(session string) (*sqlstore.Container, error
 	if err != nil {
 		return nil, err
 	}
+	if err := upgradeGatewaySchema(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("upgrade gateway schema: %w", err)
+	}
 	m.containers[session] = container
 	m.dbs[session] = db
 	return container, nil
@@ -53,5 +58,6 @@ func getDeviceFromContainer(container *sqlstore.Container) (*store.Device, error
 	if device == nil {
 		device = container.NewDevice()
 	}
+	useStatusAudience(device)
 	return device, nil
 }
@@ -0,0 +1,338 @@
+package wa
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The outbox lives next to the session stores. The dot in its name keeps it
+// from being taken for a session.
+const outboxFileName = "webhook.outbox.db"
+
+const outboxSchema = `
+CREATE TABLE IF NOT EXISTS webhook_outbox (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	session         TEXT    NOT NULL,
+	event_type      TEXT    NOT NULL,
+	payload         BLOB    NOT NULL,
+	attempts        INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at INTEGER NOT NULL,
+	last_error      TEXT    NOT NULL DEFAULT '',
+	created_at      INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS webhook_outbox_session ON webhook_outbox (session, id);
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+	id         INTEGER PRIMARY KEY,
+	session    TEXT    NOT NULL,
+	event_type TEXT    NOT NULL,
+	payload    BLOB    NOT NULL,
+	attempts   INTEGER NOT NULL,
+	last_error TEXT    NOT NULL,
+	created_at INTEGER NOT NULL,
+	failed_at  INTEGER NOT NULL
+);
+`
+
+// DeadLetter is a webhook delivery that kept failing until it was given up.
+type DeadLetter struct {
+	ID        int64
+	Session   string
+	EventType string
+	Payload   json.RawMessage
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+	FailedAt  time.Time
+}
+
+type outboxEntry struct {
+	id            int64
+	eventType     string
+	payload       []byte
+	attempts      int
+	nextAttemptAt time.Time
+}
+
+type webhookOutbox struct {
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+func outboxPath(basePath string) string {
+	if basePath == "" {
+		return outboxFileName
+	}
+	if filepath.Ext(basePath) == ".db" {
+		return filepath.Join(filepath.Dir(basePath), outboxFileName)
+	}
+	return filepath.Join(basePath, outboxFileName)
+}
+
+func (m *Manager) outboxDB() (*sql.DB, error) {
+	m.outbox.once.Do(func() {
+		path := outboxPath(m.dbBasePath)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			m.outbox.err = fmt.Errorf("mkdir outbox dir: %w", err)
+			return
+		}
+		db, err := sql.Open("sqlite", sqliteDSN(path))
+		if err != nil {
+			m.outbox.err = fmt.Errorf("open outbox: %w", err)
+			return
+		}
+		db.SetMaxOpenConns(1)
+		if _, err := db.ExecContext(context.Background(), outboxSchema); err != nil {
+			_ = db.Close()
+			m.outbox.err = fmt.Errorf("upgrade outbox schema: %w", err)
+			return
+		}
+		m.outbox.db = db
+	})
+	return m.outbox.db, m.outbox.err
+}
+
+func (m *Manager) enqueueOutbox(ctx context.Context, session, eventType string, payload []byte) error {
+	db, err := m.outboxDB()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO webhook_outbox (session, event_type, payload, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		session, eventType, payload, now, now,
+	)
+	return err
+}
+
+// nextOutboxEntry returns the session's oldest pending delivery, due or not.
+func (m *Manager) nextOutboxEntry(ctx context.Context, session string) (*outboxEntry, bool, error) {
+	db, err := m.outboxDB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		e    outboxEntry
+		next int64
+	)
+	err = db.QueryRowContext(ctx, `
+		SELECT id, event_type, payload, attempts, next_attempt_at FROM webhook_outbox
+		WHERE session = ? ORDER BY id LIMIT 1`,
+		session,
+	).Scan(&e.id, &e.eventType, &e.payload, &e.attempts, &next)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	e.nextAttemptAt = time.Unix(next, 0)
+	return &e, true, nil
+}
+
+func (m *Manager) deleteOutboxEntry(ctx context.Context, id int64) error {
+	db, err := m.outboxDB()
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `DELETE FROM webhook_outbox WHERE id = ?`, id)
+	return err
+}
+
+func (m *Manager) retryOutboxEntry(ctx context.Context, id int64, at time.Time, lastErr string) error {
+	db, err := m.outboxDB()
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		UPDATE webhook_outbox SET attempts = attempts + 1, next_attempt_at = ?, last_error = ?
+		WHERE id = ?`,
+		at.Unix(), lastErr, id,
+	)
+	return err
+}
+
+// deadLetterOutboxEntry gives up on a delivery, moving it to the dead letters.
+func (m *Manager) deadLetterOutboxEntry(ctx context.Context, id int64, lastErr string) error {
+	db, err := m.outboxDB()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_dead_letters (id, session, event_type, payload, attempts, last_error, created_at, failed_at)
+		SELECT id, session, event_type, payload, attempts + 1, ?, created_at, ? FROM webhook_outbox WHERE id = ?`,
+		lastErr, time.Now().Unix(), id,
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_outbox WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// outboxSessions lists the sessions with deliveries still pending.
+func (m *Manager) outboxSessions(ctx context.Context) ([]string, error) {
+	db, err := m.outboxDB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT DISTINCT session FROM webhook_outbox`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var session string
+		if err := rows.Scan(&session); err != nil {
+			return nil, err
+		}
+		out = append(out, session)
+	}
+	return out, rows.Err()
+}
+
+// ListDeadLetters returns the newest dead letters first, of one session or
+// of all sessions when session is empty.
+func (m *Manager) ListDeadLetters(ctx context.Context, session string, limit int) ([]DeadLetter, error) {
+	db, err := m.outboxDB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, session, event_type, payload, attempts, last_error, created_at, failed_at
+		FROM webhook_dead_letters WHERE ? = '' OR session = ?
+		ORDER BY id DESC LIMIT ?`,
+		session, session, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []DeadLetter
+	for rows.Next() {
+		var (
+			d                   DeadLetter
+			createdAt, failedAt int64
+		)
+		if err := rows.Scan(&d.ID, &d.Session, &d.EventType, &d.Payload, &d.Attempts, &d.LastError, &createdAt, &failedAt); err != nil {
+			return nil, err
+		}
+		d.CreatedAt = time.Unix(createdAt, 0)
+		d.FailedAt = time.Unix(failedAt, 0)
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// ReplayDeadLetters moves dead letters back into the outbox for another
+// round of attempts. ids selects which; when empty, every dead letter of
+// session (or of all sessions when session is empty) is replayed. It returns
+// how many were replayed.
+func (m *Manager) ReplayDeadLetters(ctx context.Context, session string, ids []int64) (int, error) {
+	db, err := m.outboxDB()
+	if err != nil {
+		return 0, err
+	}
+
+	filter, args := deadLetterFilter(session, ids)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT session FROM webhook_dead_letters WHERE `+filter, args...)
+	if err != nil {
+		return 0, err
+	}
+	var sessions []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		sessions = append(sessions, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_outbox (session, event_type, payload, next_attempt_at, created_at)
+		SELECT session, event_type, payload, ?, created_at FROM webhook_dead_letters
+		WHERE `+filter+` ORDER BY id`,
+		append([]any{time.Now().Unix()}, args...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM webhook_dead_letters WHERE `+filter, args...)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	for _, s := range sessions {
+		m.wakeWebhooks(s)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// DeleteDeadLetters discards dead letters, selected like ReplayDeadLetters.
+func (m *Manager) DeleteDeadLetters(ctx context.Context, session string, ids []int64) (int, error) {
+	db, err := m.outboxDB()
+	if err != nil {
+		return 0, err
+	}
+
+	filter, args := deadLetterFilter(session, ids)
+	res, err := db.ExecContext(ctx, `DELETE FROM webhook_dead_letters WHERE `+filter, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+func deadLetterFilter(session string, ids []int64) (string, []any) {
+	if len(ids) > 0 {
+		args := make([]any, len(ids))
+		for i, id := range ids {
+			args[i] = id
+		}
+		return "id IN (" + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + ")", args
+	}
+	return "(? = '' OR session = ?)", []any{session, session}
+}
@@ -0,0 +1,41 @@
+package wa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookTimestampHeader carries the Unix time a delivery was signed at.
+	WebhookTimestampHeader = "X-Gateway-Timestamp"
+	// WebhookSignatureHeader carries "v1=<hex>" signatures, one per active
+	// secret, separated by commas.
+	WebhookSignatureHeader = "X-Gateway-Signature"
+)
+
+// signWebhook signs "<timestamp>.<body>" with HMAC-SHA256 under every secret,
+// so receivers keep verifying while a secret is being rotated. The timestamp
+// is part of what is signed, letting receivers reject replayed deliveries.
+func signWebhook(secrets []string, at time.Time, body []byte) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+
+	sigs := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(ts))
+		mac.Write([]byte("."))
+		mac.Write(body)
+		sigs = append(sigs, "v1="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return strings.Join(sigs, ",")
+}
+
+// GenerateWebhookSecret returns a random secret for signing webhooks.
+func GenerateWebhookSecret() string {
+	return "whsec_" + rand.Text()
+}
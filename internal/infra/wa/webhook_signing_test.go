@@ -0,0 +1,68 @@
+package wa
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// verifyWebhook checks header the way a receiver holding only secret would.
+func verifyWebhook(secret string, at time.Time, body []byte, header string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(at.Unix(), 10) + "."))
+	mac.Write(body)
+	want := "v1=" + hex.EncodeToString(mac.Sum(nil))
+	return slices.Contains(strings.Split(header, ","), want)
+}
+
+func TestSignWebhook(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"message"}`)
+
+	t.Run("single secret", func(t *testing.T) {
+		header := signWebhook([]string{"current"}, at, body)
+		if n := len(strings.Split(header, ",")); n != 1 {
+			t.Fatalf("got %d signatures, want 1: %q", n, header)
+		}
+		if !verifyWebhook("current", at, body, header) {
+			t.Errorf("signature %q does not verify with the secret", header)
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		settings := SessionSettings{WebhookSecret: "current", WebhookPreviousSecret: "previous"}
+		header := signWebhook(settings.webhookSecrets(), at, body)
+		if n := len(strings.Split(header, ",")); n != 2 {
+			t.Fatalf("got %d signatures, want 2: %q", n, header)
+		}
+		for _, secret := range []string{"current", "previous"} {
+			if !verifyWebhook(secret, at, body, header) {
+				t.Errorf("signature %q does not verify with %q", header, secret)
+			}
+		}
+		if verifyWebhook("other", at, body, header) {
+			t.Errorf("signature %q verifies with an unrelated secret", header)
+		}
+	})
+
+	t.Run("signed parts", func(t *testing.T) {
+		header := signWebhook([]string{"current"}, at, body)
+		if verifyWebhook("current", at.Add(time.Second), body, header) {
+			t.Error("signature verifies with another timestamp")
+		}
+		if verifyWebhook("current", at, []byte(`{"type":"reaction"}`), header) {
+			t.Error("signature verifies with another body")
+		}
+	})
+
+	t.Run("no secrets", func(t *testing.T) {
+		if header := signWebhook(nil, at, body); header != "" {
+			t.Errorf("got %q, want no signature", header)
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package wa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	webhookTimeout = 10 * time.Second
+
+	// Failed deliveries are retried after webhookBaseBackoff, doubling up to
+	// webhookMaxBackoff, and dead-lettered after webhookMaxAttempts. That
+	// gives an endpoint roughly a day to come back.
+	webhookBaseBackoff = 5 * time.Second
+	webhookMaxBackoff  = 30 * time.Minute
+	webhookMaxAttempts = 60
+)
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// webhookRunner keeps one delivery worker per session, so a slow endpoint
+// only holds up its own session and events of a session arrive in order.
+type webhookRunner struct {
+	mu   sync.Mutex
+	ctx  context.Context
+	wake map[string]chan struct{}
+}
+
+// RunWebhooks starts delivering the outbox, picking up deliveries left by an
+// earlier run, and keeps delivering until ctx is done. Events are written to
+// the outbox as they are published, whether or not it runs yet.
+func (m *Manager) RunWebhooks(ctx context.Context) {
+	m.hooks.mu.Lock()
+	m.hooks.ctx = ctx
+	m.hooks.mu.Unlock()
+
+	pending, err := m.outboxSessions(ctx)
+	if err != nil {
+		m.log.Warnf("load webhook outbox: %v", err)
+	}
+	for _, session := range pending {
+		m.wakeWebhooks(session)
+	}
+}
+
+// enqueueWebhook writes evt to the outbox if the session's webhook wants it.
+// It runs as part of publishing, so no event is lost to a busy subscriber.
+func (m *Manager) enqueueWebhook(ctx context.Context, evt Event) {
+	settings, ok := m.webhookSettings(ctx, evt.Session)
+	if !ok || settings.WebhookURL == "" || !settings.wantsEvent(evt.Type) {
+		return
+	}
+
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		m.log.Warnf("encode %s event of %s: %v", evt.Type, evt.Session, err)
+		return
+	}
+	if err := m.enqueueOutbox(ctx, evt.Session, evt.Type, payload); err != nil {
+		m.log.Warnf("queue %s event of %s: %v", evt.Type, evt.Session, err)
+		return
+	}
+	m.wakeWebhooks(evt.Session)
+}
+
+// wakeWebhooks tells the session's worker there is something to deliver,
+// starting it if needed. Before RunWebhooks starts, deliveries just wait in
+// the outbox.
+func (m *Manager) wakeWebhooks(session string) {
+	m.hooks.mu.Lock()
+	defer m.hooks.mu.Unlock()
+
+	if m.hooks.ctx == nil {
+		return
+	}
+	wake, ok := m.hooks.wake[session]
+	if !ok {
+		wake = make(chan struct{}, 1)
+		m.hooks.wake[session] = wake
+		go m.runWebhookWorker(m.hooks.ctx, session, wake)
+	}
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) runWebhookWorker(ctx context.Context, session string, wake <-chan struct{}) {
+	for {
+		retryAt := m.deliverWebhooks(ctx, session)
+
+		var (
+			timer *time.Timer
+			due   <-chan time.Time
+		)
+		if !retryAt.IsZero() {
+			timer = time.NewTimer(time.Until(retryAt))
+			due = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-wake:
+		case <-due:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// deliverWebhooks works through the session's outbox in order. It stops at
+// the first delivery that has to wait, returning when it is due, or returns
+// the zero time once the outbox is empty.
+func (m *Manager) deliverWebhooks(ctx context.Context, session string) time.Time {
+	for ctx.Err() == nil {
+		entry, found, err := m.nextOutboxEntry(ctx, session)
+		if err != nil {
+			m.log.Warnf("load webhook outbox of %s: %v", session, err)
+			return time.Now().Add(webhookBaseBackoff)
+		}
+		if !found {
+			return time.Time{}
+		}
+		if entry.nextAttemptAt.After(time.Now()) {
+			return entry.nextAttemptAt
+		}
+
+		settings, ok := m.webhookSettings(ctx, session)
+		if !ok {
+			exists, err := m.sessionExists(session)
+			if err != nil || exists {
+				return time.Now().Add(webhookBaseBackoff)
+			}
+			// The session is gone; what it missed stays with the dead letters.
+			if err := m.deadLetterOutboxEntry(ctx, entry.id, "session deleted"); err != nil {
+				m.log.Warnf("dead-letter webhook %d: %v", entry.id, err)
+				return time.Now().Add(webhookBaseBackoff)
+			}
+			continue
+		}
+		if settings.WebhookURL == "" {
+			// Deliveries wait for a webhook URL to be set again, which wakes
+			// the worker.
+			return time.Time{}
+		}
+
+		err = postWebhook(ctx, settings.WebhookURL, settings.webhookSecrets(), entry.eventType, entry.payload)
+		if err == nil {
+			if err := m.deleteOutboxEntry(ctx, entry.id); err != nil {
+				m.log.Warnf("remove delivered webhook %d: %v", entry.id, err)
+			}
+			m.acknowledged(ctx, session, entry)
+			continue
+		}
+
+		var rejected *webhookRejectedError
+		if errors.As(err, &rejected) || entry.attempts+1 >= webhookMaxAttempts {
+			m.log.Warnf("webhook %s event of %s failed %d times, giving up: %v", entry.eventType, session, entry.attempts+1, err)
+			if err := m.deadLetterOutboxEntry(ctx, entry.id, err.Error()); err != nil {
+				m.log.Warnf("dead-letter webhook %d: %v", entry.id, err)
+				return time.Now().Add(webhookBaseBackoff)
+			}
+			continue
+		}
+
+		retryAt := time.Now().Add(webhookBackoff(entry.attempts))
+		if err := m.retryOutboxEntry(ctx, entry.id, retryAt, err.Error()); err != nil {
+			m.log.Warnf("reschedule webhook %d: %v", entry.id, err)
+		}
+		return retryAt
+	}
+	return time.Time{}
+}
+
+// webhookBackoff is the wait after a delivery failed attempts+1 times: the
+// doubling delay with jitter, so a recovering endpoint is not hit by every
+// session at once.
+func webhookBackoff(attempts int) time.Duration {
+	d := webhookMaxBackoff
+	if attempts < 20 {
+		d = min(webhookBaseBackoff<<attempts, webhookMaxBackoff)
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// webhookSettings loads the session's settings without recreating the store
+// of a session deleted since its events were published.
+func (m *Manager) webhookSettings(ctx context.Context, session string) (SessionSettings, bool) {
+	if _, err := os.Stat(dbPathForSession(m.dbBasePath, session)); err != nil {
+		return SessionSettings{}, false
+	}
+
+	settings, err := m.Settings(ctx, session)
+	if err != nil {
+		m.log.Warnf("load settings of %s: %v", session, err)
+		return SessionSettings{}, false
+	}
+	return settings, true
+}
+
+func postWebhook(ctx context.Context, url string, secrets []string, eventType string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "whatsapp-gateway")
+	req.Header.Set("X-Gateway-Event", eventType)
+	if len(secrets) > 0 {
+		now := time.Now()
+		req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(WebhookSignatureHeader, signWebhook(secrets, now, body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode <= 499 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return &webhookRejectedError{status: resp.Status}
+	}
+	return fmt.Errorf("endpoint answered %s", resp.Status)
+}
+
+// webhookRejectedError is an endpoint refusing a delivery with a client
+// error, which sending the same payload again will not change. It is
+// dead-lettered at once rather than holding up the session's later events.
+type webhookRejectedError struct {
+	status string
+}
+
+func (e *webhookRejectedError) Error() string {
+	return "endpoint rejected the delivery: " + e.status
+}
+
+// acknowledged runs once the webhook consumer has accepted a delivery.
+func (m *Manager) acknowledged(ctx context.Context, session string, entry *outboxEntry) {
+	if entry.eventType != EventMessage {
+		return
+	}
+
+	var evt struct {
+		Timestamp time.Time    `json:"timestamp"`
+		Data      MessageEvent `json:"data"`
+	}
+	if err := json.Unmarshal(entry.payload, &evt); err != nil || evt.Data.FromMe {
+		return
+	}
+
+	chat, err := types.ParseJID(evt.Data.Chat)
+	if err != nil {
+		return
+	}
+	sender, err := types.ParseJID(evt.Data.Sender)
+	if err != nil {
+		return
+	}
+	m.markAcknowledgedRead(ctx, session, chat, ReadTarget{ID: evt.Data.MessageID, Sender: sender, Timestamp: evt.Timestamp})
+}
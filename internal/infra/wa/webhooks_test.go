@@ -0,0 +1,196 @@
+package wa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	walog "go.mau.fi/whatsmeow/util/log"
+)
+
+func TestPostWebhookStatus(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantErr      bool
+		wantRejected bool
+	}{
+		{http.StatusOK, false, false},
+		{http.StatusNoContent, false, false},
+		{http.StatusBadRequest, true, true},
+		{http.StatusGone, true, true},
+		{http.StatusRequestEntityTooLarge, true, true},
+		{http.StatusRequestTimeout, true, false},
+		{http.StatusTooManyRequests, true, false},
+		{http.StatusInternalServerError, true, false},
+		{http.StatusServiceUnavailable, true, false},
+		{http.StatusMovedPermanently, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			err := postWebhook(context.Background(), srv.URL, nil, EventConnection, []byte(`{}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("postWebhook() error = %v, want error %v", err, tt.wantErr)
+			}
+			var rejected *webhookRejectedError
+			if got := errors.As(err, &rejected); got != tt.wantRejected {
+				t.Errorf("rejected = %v, want %v (error %v)", got, tt.wantRejected, err)
+			}
+		})
+	}
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu       sync.Mutex
+		statuses = map[string]int{}
+		received []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, string(body))
+		if status, ok := statuses[string(body)]; ok {
+			w.WriteHeader(status)
+		}
+	}))
+	defer srv.Close()
+
+	setup := func(t *testing.T) *Manager {
+		m := NewManager(t.TempDir(), walog.Noop)
+		if _, err := m.getContainer("s1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.SaveSettings(ctx, "s1", SessionSettings{WebhookURL: srv.URL}); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		received = nil
+		mu.Unlock()
+		return m
+	}
+	enqueue := func(t *testing.T, m *Manager, payloads ...string) {
+		for _, p := range payloads {
+			if err := m.enqueueOutbox(ctx, "s1", EventConnection, []byte(p)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	mu.Lock()
+	statuses[`"rejected"`] = http.StatusGone
+	statuses[`"failing"`] = http.StatusServiceUnavailable
+	mu.Unlock()
+
+	t.Run("rejected delivery is dead-lettered at once", func(t *testing.T) {
+		m := setup(t)
+		enqueue(t, m, `"rejected"`, `"next"`)
+
+		if retryAt := m.deliverWebhooks(ctx, "s1"); !retryAt.IsZero() {
+			t.Errorf("deliverWebhooks wants a retry at %v, want an empty outbox", retryAt)
+		}
+		mu.Lock()
+		got := append([]string(nil), received...)
+		mu.Unlock()
+		if want := []string{`"rejected"`, `"next"`}; !slices.Equal(got, want) {
+			t.Errorf("endpoint received %q, want %q", got, want)
+		}
+
+		dead, err := m.ListDeadLetters(ctx, "s1", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dead) != 1 || string(dead[0].Payload) != `"rejected"` || dead[0].Attempts != 1 {
+			t.Fatalf("dead letters = %+v, want the rejected delivery after 1 attempt", dead)
+		}
+	})
+
+	t.Run("failed delivery is retried and holds back later ones", func(t *testing.T) {
+		m := setup(t)
+		enqueue(t, m, `"failing"`, `"next"`)
+
+		retryAt := m.deliverWebhooks(ctx, "s1")
+		if !retryAt.After(time.Now()) {
+			t.Errorf("retry at %v, want a time to come", retryAt)
+		}
+		entry, found, err := m.nextOutboxEntry(ctx, "s1")
+		if err != nil || !found {
+			t.Fatalf("next outbox entry: found %v, %v", found, err)
+		}
+		if string(entry.payload) != `"failing"` || entry.attempts != 1 {
+			t.Errorf("next entry is %s after %d attempts, want the failing one after 1", entry.payload, entry.attempts)
+		}
+		mu.Lock()
+		got := append([]string(nil), received...)
+		mu.Unlock()
+		if want := []string{`"failing"`}; !slices.Equal(got, want) {
+			t.Errorf("endpoint received %q, want %q", got, want)
+		}
+		if dead, _ := m.ListDeadLetters(ctx, "s1", 10); len(dead) != 0 {
+			t.Errorf("dead letters = %+v, want none", dead)
+		}
+	})
+
+	t.Run("failed delivery is dead-lettered after the last attempt", func(t *testing.T) {
+		m := setup(t)
+		enqueue(t, m, `"failing"`, `"next"`)
+
+		entry, _, err := m.nextOutboxEntry(ctx, "s1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db, _ := m.outboxDB()
+		if _, err := db.ExecContext(ctx, `UPDATE webhook_outbox SET attempts = ? WHERE id = ?`, webhookMaxAttempts-1, entry.id); err != nil {
+			t.Fatal(err)
+		}
+
+		if retryAt := m.deliverWebhooks(ctx, "s1"); !retryAt.IsZero() {
+			t.Errorf("deliverWebhooks wants a retry at %v, want an empty outbox", retryAt)
+		}
+		dead, err := m.ListDeadLetters(ctx, "s1", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dead) != 1 || string(dead[0].Payload) != `"failing"` || dead[0].Attempts != webhookMaxAttempts {
+			t.Fatalf("dead letters = %+v, want the failing delivery after %d attempts", dead, webhookMaxAttempts)
+		}
+	})
+
+	t.Run("deliveries wait for a webhook url", func(t *testing.T) {
+		m := setup(t)
+		if err := m.SaveSettings(ctx, "s1", SessionSettings{}); err != nil {
+			t.Fatal(err)
+		}
+		enqueue(t, m, `"next"`)
+
+		if retryAt := m.deliverWebhooks(ctx, "s1"); !retryAt.IsZero() {
+			t.Errorf("deliverWebhooks wants a retry at %v, want to wait for a wake-up", retryAt)
+		}
+		if _, found, _ := m.nextOutboxEntry(ctx, "s1"); !found {
+			t.Fatal("delivery left the outbox without a webhook url")
+		}
+
+		if err := m.SaveSettings(ctx, "s1", SessionSettings{WebhookURL: srv.URL}); err != nil {
+			t.Fatal(err)
+		}
+		m.deliverWebhooks(ctx, "s1")
+		if _, found, _ := m.nextOutboxEntry(ctx, "s1"); found {
+			t.Error("delivery still in the outbox once a webhook url was set")
+		}
+	})
+}